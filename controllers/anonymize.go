@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"github.com/Festum/Vibe/models"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"os"
+	"time"
+)
+
+const (
+	anonymousName   = "Anonymized user"
+	anonymousDomain = "anonymized.invalid"
+)
+
+var errAlreadyAnonymized = newError(ErrConflict, "ALREADY_ANONYMIZED")
+
+// Anonymize erases the personal data of an account, live or deleted, and
+// keeps the document with its id so references held elsewhere stay valid.
+// Profile fields are replaced by tombstone values, the account is disabled,
+// login IPs are cleared and linked social identities, exports, webhook
+// deliveries, avatar and billing profile removed.
+func (u *User) Anonymize() error {
+	before := User{ID: u.ID, Email: u.Email, Username: u.Username}
+	if err := before.Get(); err != nil {
+		if err != ErrNotFound {
+			return err
+		}
+		if err := userCrud(&before, "deleted"); err != nil {
+			return err
+		}
+	}
+	if before.AnonymizedAt != nil {
+		return errAlreadyAnonymized
+	}
+
+	// Collect what is linked by personal data before it is gone.
+	social, err := before.socialRecords()
+	if err != nil {
+		return err
+	}
+
+	after := before
+	tombstone(&after)
+	if err := userCrud(&after, "update"); err != nil {
+		return err
+	}
+
+	if err := scrubLinkedData(&before, social); err != nil {
+		return err
+	}
+	before.dropAvatar()
+	if err := before.dropBilling(); err != nil {
+		return err
+	}
+
+	// The entry names the pseudonym only, a diff would copy the erased data
+	// into the audit trail.
+	auditRecord(AuditAnonymize, u.Actor, after.Username, nil, nil)
+	EmitUserEvent(EventAnonymized, &after)
+
+	*u = after
+	return nil
+}
+
+func tombstone(u *User) {
+	now := time.Now()
+	pseudonym := "anon-" + u.ID.Hex()
+
+	u.Username = pseudonym
+	u.Email = pseudonym + "@" + anonymousDomain
+	u.DisplayName = anonymousName
+	u.GivenName, u.FamilyName = "", ""
+	u.Phone = ""
+	u.Birth, u.Age = time.Time{}, 0
+	u.ShortBio, u.LongBio = "", ""
+	u.Avatar = ""
+	u.Social = nil
+	u.EncryptedPassword, u.Salt = "", ""
+	u.IsDisabled = true
+	u.AnonymizedAt = &now
+}
+
+// scrubLinkedData clears the login IPs of u and removes its social identities,
+// data exports and the webhook deliveries carrying its profile.
+func scrubLinkedData(u *User, social []models.Social) error {
+	mdb, err := dbSession()
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+	db := mdb.DB(conf().DB.Name)
+
+	status := models.UserStatus{}
+	err = db.C(tableName("status")).FindId(u.ID).One(&status)
+	if err != nil && err != mgo.ErrNotFound {
+		return err
+	}
+	if err == nil {
+		for i := range status.Login {
+			status.Login[i].IPv4, status.Login[i].Location = "", ""
+		}
+		if err := db.C(tableName("status")).UpdateId(u.ID, bson.M{"$set": bson.M{"login": status.Login}}); err != nil {
+			return err
+		}
+	}
+
+	if err := removeSocial(db, social); err != nil {
+		return err
+	}
+	if err := removeExports(db, u.Username); err != nil {
+		return err
+	}
+	_, err = db.C(tableName("delivery")).RemoveAll(bson.M{"user_id": u.ID})
+	return err
+}
+
+func removeSocial(db *mgo.Database, social []models.Social) error {
+	ids := []bson.ObjectId{}
+	for _, s := range social {
+		ids = append(ids, s.ID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := db.C(tableName("social")).RemoveAll(bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+// removeExports removes the exports of username and their archives.
+func removeExports(db *mgo.Database, username string) error {
+	exports := []models.Export{}
+	if err := db.C(tableName("export")).Find(bson.M{"username": username}).All(&exports); err != nil {
+		return err
+	}
+	for _, e := range exports {
+		if err := os.Remove(exportPath(e.ID)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	_, err := db.C(tableName("export")).RemoveAll(bson.M{"username": username})
+	return err
+}
+
+// AnonymizeInactive anonymizes every account, admins excepted, without a
+// login for the given number of years and returns how many were anonymized.
+func AnonymizeInactive(years int) (int, error) {
+	if years <= 0 {
+		return 0, nil
+	}
+
+	mdb, err := dbSession()
+	if err != nil {
+		return 0, err
+	}
+	_, table := getTable("user")
+	inactive := []User{}
+	err = mdb.DB(conf().DB.Name).C(table).Find(bson.M{
+		"last_login":    bson.M{"$lt": time.Now().AddDate(-years, 0, 0)},
+		"anonymized_at": bson.M{"$exists": false},
+		"role":          bson.M{"$ne": "admin"},
+	}).Select(bson.M{"_id": 1}).All(&inactive)
+	mdb.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for i := range inactive {
+		u := User{ID: inactive[i].ID, Actor: &models.Actor{Username: "inactivity-policy", Source: "system"}}
+		if err := u.Anonymize(); err != nil && err != errAlreadyAnonymized {
+			return done, err
+		}
+		done++
+	}
+
+	return done, nil
+}
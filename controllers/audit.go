@@ -0,0 +1,361 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Festum/Vibe/models"
+	"github.com/Festum/Vibe/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"reflect"
+	"runtime"
+	"sort"
+	"time"
+)
+
+const (
+	AuditCreate      = "create"
+	AuditUpdate      = "update"
+	AuditDelete      = "delete"
+	AuditRole        = "role"
+	AuditDisable     = "disable"
+	AuditEnable      = "enable"
+	AuditPassword    = "password"
+	AuditToken       = "token"
+	AuditImpersonate = "impersonate"
+	AuditRestore     = "restore"
+	AuditPurge       = "purge"
+	AuditExport      = "export"
+	AuditAnonymize   = "anonymize"
+	AuditBilling     = "billing"
+
+	redacted = "[REDACTED]"
+
+	// auditAppendRetries bounds how often an append races another writer for
+	// the next sequence number.
+	auditAppendRetries = 5
+)
+
+var (
+	// clearFields describe the state of an account rather than the person,
+	// their values are kept. Only the name of other changed fields is, the
+	// audit collection is append-only and outlives erasure.
+	clearFields = map[string]bool{
+		"username":      true,
+		"role":          true,
+		"is_disabled":   true,
+		"language":      true,
+		"created_at":    true,
+		"last_login":    true,
+		"deleted_at":    true,
+		"purge_at":      true,
+		"anonymized_at": true,
+	}
+	// noisyFields change on every write and carry no information.
+	noisyFields = map[string]bool{
+		"updated_at": true,
+		"version":    true,
+	}
+
+	logger = new(utils.Logger)
+)
+
+// AuditQuery filters the audit collection. Entries are returned in chain
+// order, starting after the AfterSeq cursor.
+type AuditQuery struct {
+	Actor    string
+	Target   string
+	Action   string
+	Since    time.Time
+	Until    time.Time
+	AfterSeq int64
+	Limit    int
+}
+
+// RecordAudit appends an entry to the audit collection and links it to the
+// previous entry of the chain.
+func RecordAudit(e *models.AuditEntry) error {
+	mdb, err := dbSession()
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+
+	col := mdb.DB(conf().DB.Name).C(tableName("audit"))
+	if err := col.EnsureIndex(mgo.Index{Key: []string{"seq"}, Unique: true}); err != nil {
+		return errors.New("Ensure Error: " + err.Error())
+	}
+	for _, key := range []string{"target", "actor.username", "action", "when"} {
+		if err := col.EnsureIndexKey(key); err != nil {
+			return errors.New("Ensure Error: " + err.Error())
+		}
+	}
+
+	if e.When.IsZero() {
+		e.When = time.Now()
+	}
+	// Mongo keeps milliseconds, hash what will be read back.
+	e.When = e.When.UTC().Truncate(time.Millisecond)
+	if e.IP == "" {
+		e.IP = e.Actor.IP
+	}
+
+	for i := 0; i < auditAppendRetries; i++ {
+		last := models.AuditEntry{}
+		err := col.Find(nil).Sort("-seq").One(&last)
+		if err != nil && err != mgo.ErrNotFound {
+			return err
+		}
+
+		e.ID = bson.NewObjectId()
+		e.Seq = last.Seq + 1
+		e.PrevHash = last.Hash
+		if e.Hash, err = AuditHash(e); err != nil {
+			return err
+		}
+
+		err = col.Insert(e)
+		if mgo.IsDup(err) {
+			continue // someone else took this sequence number
+		}
+		return err
+	}
+
+	return errors.New("AUDIT_APPEND_CONFLICT")
+}
+
+// auditRecord writes an audit entry for a finished user operation. Failures
+// are logged rather than returned, the operation itself already happened.
+func auditRecord(action string, actor *models.Actor, target string, before, after *User) {
+	changes, err := auditDiff(before, after)
+	if err == nil {
+		e := &models.AuditEntry{
+			Action:  action,
+			Actor:   auditActor(actor, target),
+			Target:  target,
+			Changes: changes,
+		}
+		err = RecordAudit(e)
+	}
+
+	if err != nil {
+		_, filename, _, _ := runtime.Caller(1)
+		logger.Error(map[string]interface{}{
+			"from":    filename,
+			"section": "Audit",
+			"action":  action,
+			"target":  target,
+			"time":    time.Now(),
+		}, err.Error())
+	}
+}
+
+// auditUpdateAction names an update after its most sensitive change.
+func auditUpdateAction(before, after *User) string {
+	switch {
+	case before.EncryptedPassword != after.EncryptedPassword:
+		return AuditPassword
+	case before.Role != after.Role:
+		return AuditRole
+	case !before.IsDisabled && after.IsDisabled:
+		return AuditDisable
+	case before.IsDisabled && !after.IsDisabled:
+		return AuditEnable
+	}
+	return AuditUpdate
+}
+
+func auditActor(actor *models.Actor, target string) models.Actor {
+	if actor == nil {
+		return models.Actor{Username: target, Source: "system"}
+	}
+	return *actor
+}
+
+// auditDiff returns the changed document fields between two versions of a
+// user. Either side may be nil for creation and deletion.
+func auditDiff(before, after *User) ([]models.FieldChange, error) {
+	b, err := auditDoc(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := auditDoc(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]bool{}
+	for k := range b {
+		fields[k] = true
+	}
+	for k := range a {
+		fields[k] = true
+	}
+
+	changes := []models.FieldChange{}
+	for f := range fields {
+		if noisyFields[f] || reflect.DeepEqual(b[f], a[f]) {
+			continue
+		}
+		fc := models.FieldChange{Field: f, Before: b[f], After: a[f]}
+		if !clearFields[f] {
+			fc.Before, fc.After = redactValue(b[f]), redactValue(a[f])
+		}
+		changes = append(changes, fc)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+
+	return changes, nil
+}
+
+// auditDoc flattens a user into the stored field names with JSON values, so
+// an entry hashes the same before and after a round trip through mongo.
+func auditDoc(u *User) (map[string]interface{}, error) {
+	doc := map[string]interface{}{}
+	if u == nil {
+		return doc, nil
+	}
+
+	raw, err := bson.Marshal(u)
+	if err != nil {
+		return nil, err
+	}
+	bdoc := bson.M{}
+	if err := bson.Unmarshal(raw, &bdoc); err != nil {
+		return nil, err
+	}
+	delete(bdoc, "_id")
+
+	j, err := json.Marshal(bdoc)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(j, &doc)
+
+	return doc, err
+}
+
+// redactValue keeps whether a field was set, not what it was set to.
+func redactValue(v interface{}) interface{} {
+	if v == nil || v == "" {
+		return v
+	}
+	return redacted
+}
+
+// AuditHash covers every field of the entry but its id and its own hash.
+func AuditHash(e *models.AuditEntry) (string, error) {
+	changes := e.Changes
+	if len(changes) == 0 {
+		changes = nil // empty changes are not stored
+	}
+	j, err := json.Marshal(struct {
+		Seq      int64                `json:"seq"`
+		Action   string               `json:"action"`
+		Actor    models.Actor         `json:"actor"`
+		Target   string               `json:"target"`
+		Changes  []models.FieldChange `json:"changes"`
+		Reason   string               `json:"reason"`
+		IP       string               `json:"ip"`
+		When     string               `json:"when"`
+		PrevHash string               `json:"prev_hash"`
+	}{e.Seq, e.Action, e.Actor, e.Target, changes, e.Reason, e.IP,
+		e.When.UTC().Format(time.RFC3339Nano), e.PrevHash})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(j)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Find returns the entries matching the query in chain order.
+func (q *AuditQuery) Find() ([]models.AuditEntry, error) {
+	filter := bson.M{}
+	if q.Actor != "" {
+		filter["actor.username"] = q.Actor
+	}
+	if q.Target != "" {
+		filter["target"] = q.Target
+	}
+	if q.Action != "" {
+		filter["action"] = q.Action
+	}
+	if r := timeRange(q.Since, q.Until); r != nil {
+		filter["when"] = r
+	}
+	if q.AfterSeq > 0 {
+		filter["seq"] = bson.M{"$gt": q.AfterSeq}
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	mdb, err := dbSession()
+	if err != nil {
+		return nil, err
+	}
+	defer mdb.Close()
+
+	entries := []models.AuditEntry{}
+	err = mdb.DB(conf().DB.Name).C(tableName("audit")).Find(filter).Sort("seq").Limit(limit).All(&entries)
+
+	return entries, err
+}
+
+// AuditChain checks entries handed over in sequence order: each follows the
+// previous one and hashes to its Hash.
+type AuditChain struct {
+	Checked int64 // entries found intact
+	prev    string
+}
+
+// Next checks e, the entry after the last one checked.
+func (c *AuditChain) Next(e *models.AuditEntry) error {
+	if e.Seq != c.Checked+1 {
+		return fmt.Errorf("AUDIT_CHAIN_BROKEN: entry %d is missing", c.Checked+1)
+	}
+	if e.PrevHash != c.prev {
+		return fmt.Errorf("AUDIT_CHAIN_BROKEN: entry %d does not follow its predecessor", e.Seq)
+	}
+	hash, err := AuditHash(e)
+	if err != nil {
+		return err
+	}
+	if hash != e.Hash {
+		return fmt.Errorf("AUDIT_CHAIN_BROKEN: entry %d has been altered", e.Seq)
+	}
+	c.Checked, c.prev = e.Seq, e.Hash
+	return nil
+}
+
+// VerifyAudit walks the whole chain and recomputes every hash. It returns the
+// number of entries checked and an error naming the first broken sequence.
+func VerifyAudit() (int64, error) {
+	mdb, err := dbSession()
+	if err != nil {
+		return 0, err
+	}
+	defer mdb.Close()
+
+	iter := mdb.DB(conf().DB.Name).C(tableName("audit")).Find(nil).Sort("seq").Iter()
+	chain := new(AuditChain)
+	e := models.AuditEntry{}
+	for iter.Next(&e) {
+		if err := chain.Next(&e); err != nil {
+			iter.Close()
+			return chain.Checked, err
+		}
+		e = models.AuditEntry{}
+	}
+
+	return chain.Checked, iter.Close()
+}
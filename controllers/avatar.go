@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"github.com/Festum/Vibe/utils"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultAvatarBaseURL = "/avatars"
+	DefaultAvatarMaxSize = 5 << 20
+	maxAvatarPixels      = 40 << 20
+)
+
+var (
+	DefaultAvatarSizes = []int{64, 128, 256}
+
+	// avatarName matches the blob names of avatars: <user id>/<stamp>/<size>.<ext>
+	avatarName = regexp.MustCompile(`^[0-9a-f]{24}/[0-9a-z]+/[0-9]+\.(jpg|png)$`)
+)
+
+// SetAvatar validates an uploaded picture, stores its square variants without
+// metadata and points the Avatar of u to the largest one.
+func (u *User) SetAvatar(data []byte) error {
+	m := avatarImager()
+	img, err := m.Decode(data)
+	if err != nil {
+		return typed(err)
+	}
+	return u.storeAvatar(func(size int) ([]byte, error) {
+		return m.JPEG(m.Square(img, size))
+	}, "jpg", "image/jpeg")
+}
+
+// SetIdenticon replaces the Avatar of u with a picture generated from its id.
+func (u *User) SetIdenticon() error {
+	m := avatarImager()
+	return u.storeAvatar(func(size int) ([]byte, error) {
+		return m.PNG(m.Identicon(u.ID.Hex(), size))
+	}, "png", "image/png")
+}
+
+func (u *User) storeAvatar(render func(size int) ([]byte, error), ext, contentType string) error {
+	current := User{ID: u.ID, Email: u.Email, Username: u.Username}
+	if err := current.Get(); err != nil {
+		return err
+	}
+
+	// A new stamp per upload, so variants can be cached forever.
+	stamp := strconv.FormatInt(time.Now().UnixNano(), 36)
+	sizes := avatarSizes()
+	names := []string{}
+	for _, size := range sizes {
+		data, err := render(size)
+		if err != nil {
+			return err
+		}
+		name := current.ID.Hex() + "/" + stamp + "/" + strconv.Itoa(size) + "." + ext
+		if err := blobStore().Put(name, contentType, data); err != nil {
+			dropBlobs(names)
+			return err
+		}
+		names = append(names, name)
+	}
+
+	next := current
+	next.Avatar = avatarBaseURL() + "/" + names[len(names)-1]
+	if err := u.save(&current, &next); err != nil {
+		dropBlobs(names)
+		return err
+	}
+	current.dropAvatar()
+
+	u.ID = current.ID
+	return u.Get()
+}
+
+// OpenAvatar returns a stored avatar variant by the name it is served under.
+func OpenAvatar(name string) (io.ReadCloser, string, error) {
+	if !avatarName.MatchString(name) {
+		return nil, "", ErrNotFound
+	}
+	return blobStore().Open(name)
+}
+
+// dropAvatar removes every variant of the avatar of u served by Vibe. Links
+// to other sites, or to the avatar of someone else, are left alone.
+func (u *User) dropAvatar() {
+	prefix := avatarBaseURL() + "/" + u.ID.Hex() + "/"
+	if !strings.HasPrefix(u.Avatar, prefix) {
+		return
+	}
+	name := strings.TrimPrefix(u.Avatar, avatarBaseURL()+"/")
+	if !avatarName.MatchString(name) {
+		return
+	}
+	dir := name[:strings.LastIndex(name, "/")+1]
+	ext := name[strings.LastIndex(name, "."):]
+
+	names := []string{}
+	for _, size := range avatarSizes() {
+		names = append(names, dir+strconv.Itoa(size)+ext)
+	}
+	dropBlobs(names)
+}
+
+func dropBlobs(names []string) {
+	for _, name := range names {
+		if err := blobStore().Delete(name); err != nil {
+			logger.Error(map[string]interface{}{
+				"section": "Blob",
+				"blob":    name,
+				"time":    time.Now(),
+			}, err.Error())
+		}
+	}
+}
+
+func avatarImager() *utils.Imager {
+	max := conf().Avatar.MaxSize << 10
+	if max <= 0 {
+		max = DefaultAvatarMaxSize
+	}
+	return &utils.Imager{MaxBytes: max, MaxPixels: maxAvatarPixels}
+}
+
+// avatarSizes returns the configured variant sizes, smallest first.
+func avatarSizes() []int {
+	sizes := []int{}
+	for _, s := range conf().Avatar.Sizes {
+		if s > 0 && s <= 1024 {
+			sizes = append(sizes, s)
+		}
+	}
+	if len(sizes) == 0 {
+		sizes = append(sizes, DefaultAvatarSizes...)
+	}
+	sort.Ints(sizes)
+	return sizes
+}
+
+// avatarBaseURL is the configured base URL of avatars, else the path of the
+// avatar route under BasePath.
+func avatarBaseURL() string {
+	if conf().Avatar.BaseURL != "" {
+		return strings.TrimSuffix(conf().Avatar.BaseURL, "/")
+	}
+	return BasePath + DefaultAvatarBaseURL
+}
@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Festum/Vibe/models"
+	"github.com/Festum/Vibe/utils"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PaymentGateway vaults card details with a payment provider. Vibe keeps the
+// token it returns and never the card itself.
+type PaymentGateway interface {
+	// Tokenize stores the card and returns the provider token for it.
+	Tokenize(number, cid, expire string) (string, error)
+	// Remove forgets a token returned by Tokenize.
+	Remove(token string) error
+}
+
+// ErrCardDeclined is returned by gateways refusing a card.
+var ErrCardDeclined = &Error{Kind: ErrBadRequest, Code: "CARD_DECLINED"}
+
+// FakeGateway is an in-memory PaymentGateway for tests and development. The
+// number 4000000000000002 is declined, as with most providers' test cards.
+type FakeGateway struct {
+	mu     sync.Mutex
+	seq    int
+	Tokens map[string]string // token to last four digits
+}
+
+func (g *FakeGateway) Tokenize(number, cid, expire string) (string, error) {
+	if number == "4000000000000002" {
+		return "", ErrCardDeclined
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.Tokens == nil {
+		g.Tokens = map[string]string{}
+	}
+	g.seq++
+	token := fmt.Sprintf("tok_fake_%d", g.seq)
+	g.Tokens[token] = number[len(number)-4:]
+	return token, nil
+}
+
+func (g *FakeGateway) Remove(token string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.Tokens, token)
+	return nil
+}
+
+// Gateway is the payment gateway used for billing profiles. It must be set by
+// the application before cards can be saved.
+var Gateway PaymentGateway
+
+// SaveBilling validates a card, hands it to the Gateway and stores the
+// resulting billing profile of u in place of the previous one. On return b
+// holds what is stored, with the card masked and the CID cleared.
+func (u *User) SaveBilling(b *models.Billing) error {
+	if Gateway == nil {
+		return newError(ErrUnavailable, "GATEWAY_NOT_CONFIGURED")
+	}
+	if err := u.Get(); err != nil {
+		return err
+	}
+
+	card := new(utils.Card)
+	number := card.Normalize(b.CardNumber)
+	verr := new(ValidationError)
+	if !card.Luhn(number) {
+		verr.Add("card_number", "INVALID_CARD_NUMBER")
+	} else if b.Type = card.Brand(number); b.Type == "" {
+		verr.Add("card_number", "UNSUPPORTED_CARD")
+	}
+	if _, err := card.Expiry(b.Expire, time.Now()); err != nil {
+		// CARD_EXPIRED or BAD_EXPIRY
+		verr.Add("expire", err.Error())
+	}
+	if b.Type != "" && (len(b.Cid) != card.CidLength(b.Type) || strings.Trim(b.Cid, "0123456789") != "") {
+		verr.AddParams("cid", "BAD_CID", map[string]interface{}{"Digits": card.CidLength(b.Type)})
+	}
+	if err := verr.Err(); err != nil {
+		b.CardNumber, b.Cid = "", ""
+		return err
+	}
+
+	token, err := Gateway.Tokenize(number, b.Cid, b.Expire)
+	b.CardNumber, b.Cid = "", ""
+	if err != nil {
+		return err
+	}
+
+	old, err := u.loadBilling()
+	if err != nil && err != ErrNotFound {
+		Gateway.Remove(token)
+		return err
+	}
+
+	b.ID = u.ID
+	b.Token = token
+	b.Last4 = number[len(number)-4:]
+	b.Expire = strings.Replace(b.Expire, "/", "", 1)
+	b.UpdatedAt = time.Now()
+	if err := billingCrud(b, "upsert"); err != nil {
+		Gateway.Remove(token)
+		return err
+	}
+	if old != nil && old.Token != token {
+		Gateway.Remove(old.Token)
+	}
+
+	auditRecord(AuditBilling, u.Actor, u.Username, nil, nil)
+	maskBilling(b)
+	return nil
+}
+
+// GetBilling returns the billing profile of u with the card masked.
+func (u *User) GetBilling() (*models.Billing, error) {
+	if err := u.Get(); err != nil {
+		return nil, err
+	}
+	b, err := u.loadBilling()
+	if err != nil {
+		return nil, err
+	}
+	maskBilling(b)
+	return b, nil
+}
+
+// RemoveBilling deletes the billing profile of u and its gateway token.
+func (u *User) RemoveBilling() error {
+	if err := u.Get(); err != nil {
+		return err
+	}
+	b, err := u.loadBilling()
+	if err != nil {
+		return err
+	}
+	if Gateway != nil {
+		if err := Gateway.Remove(b.Token); err != nil {
+			return err
+		}
+	}
+	if err := billingCrud(b, "delete"); err != nil {
+		return err
+	}
+
+	auditRecord(AuditBilling, u.Actor, u.Username, nil, nil)
+	return nil
+}
+
+func (u *User) loadBilling() (*models.Billing, error) {
+	b := &models.Billing{ID: u.ID}
+	if err := billingCrud(b, "read"); err != nil {
+		return nil, typed(err)
+	}
+	return b, nil
+}
+
+// dropBilling removes the payment method of u, at the gateway as well.
+func (u *User) dropBilling() error {
+	b, err := u.loadBilling()
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if Gateway != nil {
+		Gateway.Remove(b.Token)
+	}
+	return billingCrud(b, "delete")
+}
+
+func maskBilling(b *models.Billing) {
+	b.CardNumber = new(utils.Card).Mask(b.Last4)
+	b.Cid = ""
+}
+
+func billingCrud(b *models.Billing, action string) error {
+	mdb, err := dbSession()
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+
+	col := mdb.DB(conf().DB.Name).C(tableName("billing"))
+	switch action {
+	case "read":
+		return findOpen(col.FindId(b.ID), billingSealed, b)
+	case "upsert":
+		doc, err := sealedDoc(b, billingSealed)
+		if err != nil {
+			return err
+		}
+		_, err = col.UpsertId(b.ID, doc)
+		return err
+	case "delete":
+		return col.RemoveId(b.ID)
+	}
+	return errors.New("BAD_ACTION")
+}
@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"io"
+	"io/ioutil"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// BlobStore keeps the files served by Vibe, such as avatars. Names are
+// slash separated paths chosen by Vibe.
+type BlobStore interface {
+	Put(name, contentType string, data []byte) error
+	// Open returns the content of a blob and its content type, or
+	// ErrNotFound.
+	Open(name string) (io.ReadCloser, string, error)
+	Delete(name string) error
+}
+
+// LocalStore keeps blobs as files under Dir.
+type LocalStore struct {
+	Dir string
+}
+
+// GridFSStore keeps blobs in the GridFS files of the Vibe database.
+type GridFSStore struct {
+	Prefix string
+}
+
+var (
+	// Blobs is the store of uploaded files. It is chosen from the [avatar]
+	// section on first use unless the application sets it.
+	Blobs   BlobStore
+	blobsMu sync.Mutex
+)
+
+func blobStore() BlobStore {
+	blobsMu.Lock()
+	defer blobsMu.Unlock()
+
+	if Blobs == nil {
+		switch conf().Avatar.Store {
+		case "gridfs":
+			Blobs = &GridFSStore{Prefix: "blob"}
+		default:
+			dir := conf().Avatar.Dir
+			if dir == "" {
+				dir = filepath.Join(os.TempDir(), "vibe-blob")
+			}
+			Blobs = &LocalStore{Dir: dir}
+		}
+	}
+	return Blobs
+}
+
+func (s *LocalStore) path(name string) (string, error) {
+	clean := filepath.Clean("/" + name)
+	if clean == "/" || strings.Contains(name, "..") {
+		return "", errors.New("BAD_BLOB_NAME")
+	}
+	return filepath.Join(s.Dir, clean), nil
+}
+
+func (s *LocalStore) Put(name, contentType string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// Write aside and rename, readers never see a partial file.
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *LocalStore) Open(name string) (io.ReadCloser, string, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return f, mime.TypeByExtension(filepath.Ext(path)), nil
+}
+
+func (s *LocalStore) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *GridFSStore) Put(name, contentType string, data []byte) error {
+	mdb, err := dbSession()
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+
+	fs := mdb.DB(conf().DB.Name).GridFS(s.Prefix)
+	f, err := fs.Create(name)
+	if err != nil {
+		return err
+	}
+	f.SetContentType(contentType)
+	if _, err := io.Copy(f, bytes.NewReader(data)); err != nil {
+		f.Abort()
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// Drop older files of the same name once the new one is complete.
+	old := []struct {
+		ID interface{} `bson:"_id"`
+	}{}
+	if err := fs.Find(bson.M{"filename": name, "_id": bson.M{"$ne": f.Id()}}).All(&old); err != nil {
+		return err
+	}
+	for _, o := range old {
+		if err := fs.RemoveId(o.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gridFile closes the session of a GridFS file along with it.
+type gridFile struct {
+	*mgo.GridFile
+	session *mgo.Session
+}
+
+func (f *gridFile) Close() error {
+	err := f.GridFile.Close()
+	f.session.Close()
+	return err
+}
+
+func (s *GridFSStore) Open(name string) (io.ReadCloser, string, error) {
+	mdb, err := dbSession()
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := mdb.DB(conf().DB.Name).GridFS(s.Prefix).Open(name)
+	if err != nil {
+		mdb.Close()
+		return nil, "", typed(err)
+	}
+	return &gridFile{GridFile: f, session: mdb}, f.ContentType(), nil
+}
+
+func (s *GridFSStore) Delete(name string) error {
+	mdb, err := dbSession()
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+
+	err = mdb.DB(conf().DB.Name).GridFS(s.Prefix).Remove(name)
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return err
+}
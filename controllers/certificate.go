@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"crypto/x509"
+	"github.com/dgrijalva/jwt-go"
+	"strings"
+)
+
+// certificateRoles are the roles services authenticating by client
+// certificate can have.
+var certificateRoles = []string{"bot", "api"}
+
+var errCertificateNotAllowed = newError(ErrForbidden, "CERTIFICATE_NOT_ALLOWED")
+
+// CertificateToken authenticates a verified client certificate of mutual TLS
+// as the bot or api account it maps to: the username of its common name in
+// [http.tls.clients], else the common name itself. The token is not signed,
+// it carries the claims of one issued to the account for the request only.
+func CertificateToken(cert *x509.Certificate) (*jwt.Token, error) {
+	name := cert.Subject.CommonName
+	if name == "" {
+		return nil, errCertificateNotAllowed
+	}
+	// Keys of the configuration are lower case.
+	username, ok := conf().HTTP.TLS.Clients[strings.ToLower(name)]
+	if !ok {
+		username = name
+	}
+
+	u := &User{Username: username}
+	if err := u.Get(); err != nil || u.IsDisabled || !certificateRole(u.Role) {
+		return nil, errCertificateNotAllowed
+	}
+
+	claims := u.newClaims("mtls", TokenTTL())
+	if err := Hooks.runToken(u, claims); err != nil {
+		return nil, err
+	}
+	return &jwt.Token{
+		Method: jwt.GetSigningMethod(conf().JWT.SigningMethod),
+		Header: map[string]interface{}{"alg": conf().JWT.SigningMethod},
+		Claims: claims,
+		Valid:  true,
+	}, nil
+}
+
+func certificateRole(role string) bool {
+	for _, r := range certificateRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,342 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Festum/Vibe/models"
+	"github.com/Festum/Vibe/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sealedField is a document field tagged `encrypt:"true"`, or
+// `encrypt:"index"` to also keep a blind index for exact-match lookups.
+// Values are sealed for their collection, document and field.
+type sealedField struct {
+	collection string
+	key        string
+	index      bool
+	typ        reflect.Type
+}
+
+var (
+	ring       *utils.Keyring
+	ringErr    error
+	ringLoaded bool
+	ringMu     sync.Mutex
+
+	userSealed    = sealedFieldsOf("user", models.User{})
+	billingSealed = sealedFieldsOf("billing", models.Billing{})
+	// The payload of a delivery carries the account data in clear.
+	deliverySealed = sealedFieldsOf("delivery", models.Delivery{})
+
+	errNoDocumentID = errors.New("KEYRING_NO_DOCUMENT_ID")
+)
+
+// activeKeyring returns the keyring of the [crypto] section. It is nil when
+// no key is configured, values are then stored in clear.
+func activeKeyring() (*utils.Keyring, error) {
+	ringMu.Lock()
+	defer ringMu.Unlock()
+	if !ringLoaded {
+		ring, ringErr = loadKeyring()
+		ringLoaded = true
+	}
+	return ring, ringErr
+}
+
+// ReloadKeyring drops the keyring, the next use builds it again from the
+// current [crypto] section.
+func ReloadKeyring() {
+	ringMu.Lock()
+	ringLoaded = false
+	ringMu.Unlock()
+}
+
+func loadKeyring() (*utils.Keyring, error) {
+	if len(conf().Crypto.Keys) == 0 {
+		return nil, nil
+	}
+	k := &utils.Keyring{Keys: map[string][]byte{}, Primary: strings.ToLower(conf().Crypto.Primary)}
+	for id, key := range conf().Crypto.Keys {
+		raw, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, errors.New("KEYRING_BAD_KEY")
+		}
+		k.Keys[strings.ToLower(id)] = raw
+	}
+	var err error
+	if k.IndexKey, err = base64.StdEncoding.DecodeString(conf().Crypto.IndexKey); err != nil {
+		return nil, errors.New("KEYRING_BAD_INDEX_KEY")
+	}
+	if err := k.Check(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// sealedFieldsOf lists the tagged fields of v, stored in collection. The
+// collection is the logical one, renaming its table keeps values readable.
+func sealedFieldsOf(collection string, v interface{}) []sealedField {
+	fields := []sealedField{}
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("encrypt")
+		if tag == "" {
+			continue
+		}
+		fields = append(fields, sealedField{collection: collection, key: bsonKey(f), index: tag == "index", typ: f.Type})
+	}
+	return fields
+}
+
+// bsonKey is the name mgo stores a struct field under.
+func bsonKey(f reflect.StructField) string {
+	if name := strings.Split(f.Tag.Get("bson"), ",")[0]; name != "" {
+		return name
+	}
+	return strings.ToLower(f.Name)
+}
+
+// ad is the additional data of the value of f in the document id.
+func (f sealedField) ad(id bson.ObjectId) []byte {
+	return utils.FieldAD(f.collection, id.Hex(), f.key)
+}
+
+// open decrypts a value of f in the document id. Values sealed before they
+// were bound to their document open with the field name alone, until
+// RotateKeys seals them again.
+func (f sealedField) open(k *utils.Keyring, id bson.ObjectId, sealed string) ([]byte, bool, error) {
+	plain, err := k.Open(sealed, f.ad(id))
+	if err == nil {
+		return plain, false, nil
+	}
+	if plain, lerr := k.Open(sealed, []byte(f.key)); lerr == nil {
+		return plain, true, nil
+	}
+	return nil, false, err
+}
+
+func docID(doc map[string]interface{}) bson.ObjectId {
+	id, _ := doc["_id"].(bson.ObjectId)
+	return id
+}
+
+func isEmpty(v interface{}) bool {
+	if t, ok := v.(time.Time); ok {
+		return t.IsZero()
+	}
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+// sealDoc encrypts the tagged fields of the document id about to be stored.
+// Empty values are left as they are, there is nothing to hide.
+func sealDoc(doc map[string]interface{}, id bson.ObjectId, fields []sealedField) error {
+	k, err := activeKeyring()
+	if err != nil || k == nil {
+		return err
+	}
+	if id == "" {
+		return errNoDocumentID
+	}
+
+	for _, f := range fields {
+		v, ok := doc[f.key]
+		if f.index {
+			delete(doc, f.key+"_bidx")
+		}
+		if !ok || isEmpty(v) {
+			continue
+		}
+		if s, ok := v.(string); ok && k.IsSealed(s) {
+			continue
+		}
+
+		if f.index {
+			doc[f.key+"_bidx"] = k.BlindIndex(fmt.Sprint(v))
+		}
+		plain, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if doc[f.key], err = k.Seal(plain, f.ad(id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openDoc decrypts the tagged fields of a stored document in place.
+func openDoc(doc bson.M, fields []sealedField) error {
+	for _, f := range fields {
+		delete(doc, f.key+"_bidx")
+		s, ok := doc[f.key].(string)
+		if !ok || !new(utils.Keyring).IsSealed(s) {
+			continue
+		}
+
+		k, err := activeKeyring()
+		if err != nil {
+			return err
+		}
+		if k == nil {
+			return errors.New("KEYRING_NOT_CONFIGURED")
+		}
+		plain, _, err := f.open(k, docID(doc), s)
+		if err != nil {
+			return err
+		}
+		v := reflect.New(f.typ)
+		if err := json.Unmarshal(plain, v.Interface()); err != nil {
+			return err
+		}
+		doc[f.key] = v.Elem().Interface()
+	}
+	return nil
+}
+
+// sealedDoc converts v to a document with its tagged fields encrypted.
+func sealedDoc(v interface{}, fields []sealedField) (bson.M, error) {
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	doc := bson.M{}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return doc, sealDoc(doc, docID(doc), fields)
+}
+
+// openInto decrypts a stored document and decodes it into v.
+func openInto(doc bson.M, fields []sealedField, v interface{}) error {
+	if err := openDoc(doc, fields); err != nil {
+		return err
+	}
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, v)
+}
+
+func findOpen(q *mgo.Query, fields []sealedField, v interface{}) error {
+	doc := bson.M{}
+	if err := q.One(&doc); err != nil {
+		return err
+	}
+	return openInto(doc, fields, v)
+}
+
+// RotateKeys rewraps every sealed value of the user, billing and delivery
+// collections with the primary key, and seals values stored before
+// encryption was turned on or before values were bound to their document. It
+// can run while the service is up: a document changed meanwhile is left for
+// the next run. progress, when set, is called with the number of documents
+// rewritten so far.
+func RotateKeys(progress func(int)) (int, error) {
+	k, err := activeKeyring()
+	if err != nil {
+		return 0, err
+	}
+	if k == nil {
+		return 0, errors.New("KEYRING_NOT_CONFIGURED")
+	}
+
+	mdb, err := dbSession()
+	if err != nil {
+		return 0, err
+	}
+	defer mdb.Close()
+
+	_, table := getTable("user")
+	rewritten := 0
+	for _, target := range []struct {
+		col    *mgo.Collection
+		fields []sealedField
+	}{
+		{mdb.DB(conf().DB.Name).C(table), userSealed},
+		{mdb.DB(conf().DB.Name).C(tableName("billing")), billingSealed},
+		{mdb.DB(conf().DB.Name).C(tableName("delivery")), deliverySealed},
+	} {
+		iter := target.col.Find(nil).Iter()
+		doc := bson.M{}
+		for iter.Next(&doc) {
+			cond, set, err := rotateDoc(k, doc, target.fields)
+			if err != nil {
+				iter.Close()
+				return rewritten, err
+			}
+			if len(set) > 0 {
+				err = target.col.Update(cond, bson.M{"$set": set})
+				if err == nil {
+					rewritten++
+					if progress != nil {
+						progress(rewritten)
+					}
+				} else if err != mgo.ErrNotFound {
+					iter.Close()
+					return rewritten, err
+				}
+			}
+			doc = bson.M{}
+		}
+		if err := iter.Close(); err != nil {
+			return rewritten, err
+		}
+	}
+
+	return rewritten, nil
+}
+
+// rotateDoc returns the update bringing doc to the primary key, conditioned
+// on the values it replaces.
+func rotateDoc(k *utils.Keyring, doc bson.M, fields []sealedField) (bson.M, bson.M, error) {
+	id := docID(doc)
+	cond, set := bson.M{"_id": id}, bson.M{}
+	for _, f := range fields {
+		v, ok := doc[f.key]
+		if !ok || isEmpty(v) {
+			continue
+		}
+		if s, ok := v.(string); ok && k.IsSealed(s) {
+			plain, legacy, err := f.open(k, id, s)
+			if err != nil {
+				return nil, nil, err
+			}
+			if legacy {
+				if set[f.key], err = k.Seal(plain, f.ad(id)); err != nil {
+					return nil, nil, err
+				}
+				cond[f.key] = s
+				continue
+			}
+			if k.KeyID(s) == k.Primary {
+				continue
+			}
+			rewrapped, err := k.Rewrap(s)
+			if err != nil {
+				return nil, nil, err
+			}
+			cond[f.key], set[f.key] = s, rewrapped
+			continue
+		}
+
+		fresh := map[string]interface{}{f.key: v}
+		if err := sealDoc(fresh, id, []sealedField{f}); err != nil {
+			return nil, nil, err
+		}
+		cond[f.key] = v
+		for key, value := range fresh {
+			set[key] = value
+		}
+	}
+	return cond, set, nil
+}
@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"gopkg.in/mgo.v2"
+	"reflect"
+	"strings"
+)
+
+// The kinds of the errors of controllers, test them with errors.Is. The text
+// of every error is its message code, more precise than its kind.
+var (
+	ErrNotFound    = errors.New("NOT_FOUND")
+	ErrConflict    = errors.New("CONFLICT")
+	ErrValidation  = errors.New("VALIDATION_FAILED")
+	ErrForbidden   = errors.New("FORBIDDEN")
+	ErrBadRequest  = errors.New("BAD_REQUEST")
+	ErrUnavailable = errors.New("SERVICE_UNAVAILABLE")
+
+	ErrVersionConflict = &Error{Kind: ErrConflict, Code: "VERSION_CONFLICT"}
+	ErrAccountExists   = &Error{Kind: ErrConflict, Code: "ACCOUNT_EXISTS"}
+)
+
+// Error is an error of a kind with its own message code, such as
+// VERSION_CONFLICT, a conflict.
+type Error struct {
+	Kind error
+	Code string
+}
+
+func (e *Error) Error() string {
+	return e.Code
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func newError(kind error, code string) error {
+	return &Error{Kind: kind, Code: code}
+}
+
+// codeKinds are the kinds of the errors of utils and of the driver.
+var codeKinds = map[string]error{
+	"BAD_PATCH":         ErrBadRequest,
+	"PATH_NOT_FOUND":    ErrBadRequest,
+	"PATCH_TEST_FAILED": ErrConflict,
+	"IMAGE_TOO_LARGE":   ErrBadRequest,
+	"UNSUPPORTED_IMAGE": ErrBadRequest,
+	"BAD_IMAGE":         ErrBadRequest,
+}
+
+// typed gives an error of utils or of the driver its kind, other errors are
+// returned as they are. Not found documents become ErrNotFound.
+func typed(err error) error {
+	if err == nil {
+		return nil
+	}
+	if err == mgo.ErrNotFound {
+		return ErrNotFound
+	}
+	if _, ok := err.(*Error); ok {
+		return err
+	}
+	if kind, ok := codeKinds[err.Error()]; ok {
+		return newError(kind, err.Error())
+	}
+	return err
+}
+
+// FieldError explains why one request field was refused. Code is stable,
+// Reason is its text in the language of the response.
+type FieldError struct {
+	Field  string                 `json:"field"`
+	Code   string                 `json:"code"`
+	Reason string                 `json:"reason"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// ValidationError lists every invalid field of a request.
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+var validatorCodes = map[string]string{
+	"required": "REQUIRED",
+	"email":    "INVALID_EMAIL",
+	"url":      "INVALID_URL",
+	"alphanum": "NOT_ALPHANUMERIC",
+	"role":     "UNKNOWN_ROLE",
+}
+
+// Unwrap makes every ValidationError an ErrValidation.
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+func (e *ValidationError) Error() string {
+	reasons := []string{}
+	for _, f := range e.Fields {
+		reasons = append(reasons, f.Field+": "+f.Reason)
+	}
+	return "VALIDATION_FAILED: " + strings.Join(reasons, "; ")
+}
+
+// Add refuses a field for the reason of a message code.
+func (e *ValidationError) Add(field, code string) {
+	e.AddParams(field, code, nil)
+}
+
+// AddParams refuses a field for a reason taking parameters.
+func (e *ValidationError) AddParams(field, code string, params map[string]interface{}) {
+	e.Fields = append(e.Fields, FieldError{
+		Field:  field,
+		Code:   code,
+		Reason: Localize("", code, params),
+		Params: params,
+	})
+}
+
+// Err returns nil when no field was refused.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Localize returns a copy of e with the reasons in lang.
+func (e *ValidationError) Localize(lang string) *ValidationError {
+	l := &ValidationError{Fields: make([]FieldError, len(e.Fields))}
+	for i, f := range e.Fields {
+		f.Reason = Localize(lang, f.Code, f.Params)
+		l.Fields[i] = f
+	}
+	return l
+}
+
+// fromValidator turns the errors of govalidator on v into a ValidationError,
+// so they carry message codes and JSON field names.
+func fromValidator(err error, v interface{}) error {
+	errs, ok := err.(govalidator.Errors)
+	if !ok {
+		return err
+	}
+	verr := new(ValidationError)
+	for _, e := range errs.Errors() {
+		ge, ok := e.(govalidator.Error)
+		if !ok {
+			verr.Add("", "INVALID")
+			continue
+		}
+		code, ok := validatorCodes[ge.Validator]
+		if !ok {
+			code = "INVALID"
+		}
+		verr.Add(jsonFieldName(v, ge.Name), code)
+	}
+	return verr.Err()
+}
+
+// jsonFieldName is the JSON name of a struct field of v.
+func jsonFieldName(v interface{}, name string) string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if f, ok := t.FieldByName(name); ok {
+		if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+			return tag
+		}
+	}
+	return strings.ToLower(name)
+}
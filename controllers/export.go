@@ -0,0 +1,443 @@
+package controllers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"github.com/Festum/Vibe/models"
+	"github.com/Festum/Vibe/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExportPending = "pending"
+	ExportRunning = "running"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+	ExportExpired = "expired"
+
+	ExportFormat         = "vibe-export/1"
+	DefaultExportLinkTTL = 24 * time.Hour
+
+	// exportLease is how long an export may stay pending or running. Past it
+	// the export is taken as lost, by a restart for instance, and failed.
+	exportLease    = time.Hour
+	exportTimedOut = "EXPORT_TIMED_OUT"
+	// exportKeyLabel derives the key of download links from the signing key.
+	exportKeyLabel = "export"
+)
+
+var (
+	errBadSignature = newError(ErrForbidden, "BAD_SIGNATURE")
+	errLinkExpired  = newError(ErrNotFound, "LINK_EXPIRED")
+	errNoPublicURL  = errors.New("PUBLIC_URL_NOT_CONFIGURED")
+)
+
+// exportManifest describes the archive, it is the first file of every
+// export.
+type exportManifest struct {
+	Format      string    `json:"format"`
+	Username    string    `json:"username"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Files       []string  `json:"files"`
+}
+
+// ExportData writes a zip archive of everything stored about u: the profile,
+// linked social accounts, status and login history, the audit entries where u
+// is the actor or the target, and the masked billing profile. Vibe keeps no
+// consents or API keys, applications storing them export them themselves.
+func (u *User) ExportData(w io.Writer) error {
+	if err := u.Get(); err != nil {
+		return err
+	}
+
+	social, err := u.socialRecords()
+	if err != nil {
+		return err
+	}
+	status, err := u.status()
+	if err != nil {
+		return err
+	}
+	audit, err := auditTrailOf(u.Username)
+	if err != nil {
+		return err
+	}
+	billing, err := u.loadBilling()
+	if err == nil {
+		maskBilling(billing)
+	} else if err != ErrNotFound {
+		return err
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", u},
+		{"social.json", social},
+		{"status.json", status},
+		{"audit.json", audit},
+		{"billing.json", billing},
+	}
+
+	manifest := exportManifest{Format: ExportFormat, Username: u.Username, GeneratedAt: time.Now().UTC()}
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, f.name)
+	}
+
+	z := zip.NewWriter(w)
+	if err := writeJSONEntry(z, "manifest.json", manifest); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := writeJSONEntry(z, f.name, f.data); err != nil {
+			return err
+		}
+	}
+	if err := z.Close(); err != nil {
+		return err
+	}
+
+	auditRecord(AuditExport, u.Actor, u.Username, nil, nil)
+	return nil
+}
+
+func writeJSONEntry(z *zip.Writer, name string, v interface{}) error {
+	w, err := z.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// socialRecords returns the social logins linked to u, by email or by the
+// provider user ids kept in the profile.
+func (u *User) socialRecords() ([]models.Social, error) {
+	mdb, err := dbSession()
+	if err != nil {
+		return nil, err
+	}
+	defer mdb.Close()
+
+	or := []bson.M{{"data.email": u.Email}}
+	ids := []string{}
+	for _, id := range u.Social {
+		ids = append(ids, id)
+	}
+	if len(ids) > 0 {
+		or = append(or, bson.M{"data.userid": bson.M{"$in": ids}})
+	}
+
+	records := []models.Social{}
+	err = mdb.DB(conf().DB.Name).C(tableName("social")).Find(bson.M{"$or": or}).All(&records)
+	return records, err
+}
+
+// status returns the status document of u, nil when none was recorded.
+func (u *User) status() (*models.UserStatus, error) {
+	mdb, err := dbSession()
+	if err != nil {
+		return nil, err
+	}
+	defer mdb.Close()
+
+	status := new(models.UserStatus)
+	err = mdb.DB(conf().DB.Name).C(tableName("status")).FindId(u.ID).One(status)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	return status, err
+}
+
+// auditTrailOf returns every audit entry where username is the actor or the
+// target, in chain order.
+func auditTrailOf(username string) ([]models.AuditEntry, error) {
+	mdb, err := dbSession()
+	if err != nil {
+		return nil, err
+	}
+	defer mdb.Close()
+
+	entries := []models.AuditEntry{}
+	err = mdb.DB(conf().DB.Name).C(tableName("audit")).Find(bson.M{"$or": []bson.M{
+		{"target": username},
+		{"actor.username": username},
+	}}).Sort("seq").All(&entries)
+	return entries, err
+}
+
+// RequestExport queues an export of u and builds it in the background. An
+// export of u that is still being built is returned instead of a new one,
+// unless it outlived its lease.
+func (u *User) RequestExport() (*models.Export, error) {
+	if err := u.Get(); err != nil {
+		return nil, err
+	}
+
+	mdb, err := dbSession()
+	if err != nil {
+		return nil, err
+	}
+	defer mdb.Close()
+	col := mdb.DB(conf().DB.Name).C(tableName("export"))
+
+	if err := failStaleExports(col); err != nil {
+		return nil, err
+	}
+	e := new(models.Export)
+	err = col.Find(bson.M{
+		"username": u.Username,
+		"status":   bson.M{"$in": []string{ExportPending, ExportRunning}},
+	}).One(e)
+	if err == nil {
+		return e, nil
+	}
+	if err != mgo.ErrNotFound {
+		return nil, err
+	}
+
+	e = &models.Export{
+		ID:        bson.NewObjectId(),
+		Username:  u.Username,
+		Status:    ExportPending,
+		CreatedAt: time.Now(),
+	}
+	if err := col.Insert(e); err != nil {
+		return nil, err
+	}
+
+	owner := User{Username: u.Username, Actor: u.Actor}
+	go runExport(&owner, e.ID)
+
+	return e, nil
+}
+
+// failStaleExports fails the exports pending or running past their lease.
+func failStaleExports(col *mgo.Collection) error {
+	_, err := col.UpdateAll(bson.M{
+		"status":     bson.M{"$in": []string{ExportPending, ExportRunning}},
+		"created_at": bson.M{"$lt": time.Now().Add(-exportLease)},
+	}, bson.M{"$set": bson.M{"status": ExportFailed, "error": exportTimedOut}})
+	return err
+}
+
+func runExport(u *User, id bson.ObjectId) {
+	err := setExport(id, bson.M{"status": ExportRunning})
+	if err == nil {
+		err = buildExport(u, id)
+	}
+	if err != nil {
+		logger.Error(map[string]interface{}{
+			"section": "Export",
+			"export":  id.Hex(),
+			"time":    time.Now(),
+		}, err.Error())
+		setExport(id, bson.M{"status": ExportFailed, "error": err.Error()})
+		return
+	}
+
+	e, err := GetExport(u.Username, id)
+	if err != nil {
+		return
+	}
+	link, err := ExportURL(e)
+	if err != nil {
+		logger.Error(map[string]interface{}{
+			"section": "Export",
+			"export":  id.Hex(),
+			"time":    time.Now(),
+		}, err.Error())
+		return
+	}
+	Notify(u, "email.export_ready", map[string]interface{}{"URL": link, "Expires": e.ExpiresAt})
+}
+
+func buildExport(u *User, id bson.ObjectId) error {
+	if err := os.MkdirAll(exportDir(), 0700); err != nil {
+		return err
+	}
+	path := exportPath(id)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if err := u.ExportData(f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return setExport(id, bson.M{
+		"status":     ExportReady,
+		"size":       info.Size(),
+		"ready_at":   now,
+		"expires_at": now.Add(exportLinkTTL()),
+	})
+}
+
+func setExport(id bson.ObjectId, fields bson.M) error {
+	mdb, err := dbSession()
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+
+	return mdb.DB(conf().DB.Name).C(tableName("export")).UpdateId(id, bson.M{"$set": fields})
+}
+
+// GetExport returns an export of username, with its download link once it is
+// ready.
+func GetExport(username string, id bson.ObjectId) (*models.Export, error) {
+	mdb, err := dbSession()
+	if err != nil {
+		return nil, err
+	}
+	defer mdb.Close()
+
+	e := new(models.Export)
+	err = mdb.DB(conf().DB.Name).C(tableName("export")).Find(bson.M{"_id": id, "username": username}).One(e)
+	if err != nil {
+		return nil, typed(err)
+	}
+	if e.Status == ExportReady && time.Now().After(e.ExpiresAt) {
+		e.Status = ExportExpired
+	}
+	if e.Status == ExportReady {
+		e.URL = ExportLink(e)
+	}
+	return e, nil
+}
+
+// BasePath is the path the routes of Vibe are mounted under, "" at the root.
+// It starts the links handed out, such as export downloads.
+var BasePath string
+
+// ExportLink returns the signed download path of a ready export. The link
+// needs no token and stops working when the export expires.
+func ExportLink(e *models.Export) string {
+	expires := strconv.FormatInt(e.ExpiresAt.Unix(), 10)
+	v := url.Values{}
+	v.Set("expires", expires)
+	v.Set("signature", exportSignature(e.ID, e.ExpiresAt))
+	return BasePath + "/export/" + e.ID.Hex() + "?" + v.Encode()
+}
+
+// ExportURL is the absolute download link of a ready export, for emails. It
+// starts with the public URL of [http].
+func ExportURL(e *models.Export) (string, error) {
+	base := strings.TrimSuffix(conf().HTTP.PublicURL, "/")
+	if base == "" {
+		return "", errNoPublicURL
+	}
+	return base + ExportLink(e), nil
+}
+
+func exportSigner() *utils.WebhookSigner {
+	return &utils.WebhookSigner{Secret: utils.DeriveKey(conf().JWT.SigningKey, exportKeyLabel)}
+}
+
+func exportSignature(id bson.ObjectId, expires time.Time) string {
+	signer := exportSigner()
+	sig := signer.Sign(expires, []byte(id.Hex()))
+	// The expiry travels in its own parameter, keep the digest only.
+	return strings.SplitN(sig, ",v1=", 2)[1]
+}
+
+// OpenExport checks a download link and returns the path of the archive.
+func OpenExport(id, expires, signature string) (string, *models.Export, error) {
+	if !bson.IsObjectIdHex(id) {
+		return "", nil, errBadSignature
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return "", nil, errBadSignature
+	}
+	if exportSigner().Verify("t="+expires+",v1="+signature, []byte(id), 0) != nil {
+		return "", nil, errBadSignature
+	}
+	if time.Now().After(time.Unix(unix, 0)) {
+		return "", nil, errLinkExpired
+	}
+
+	mdb, err := dbSession()
+	if err != nil {
+		return "", nil, err
+	}
+	defer mdb.Close()
+
+	e := new(models.Export)
+	if err := mdb.DB(conf().DB.Name).C(tableName("export")).FindId(bson.ObjectIdHex(id)).One(e); err != nil {
+		return "", nil, typed(err)
+	}
+	if e.Status != ExportReady || e.ExpiresAt.Unix() != unix {
+		return "", nil, errLinkExpired
+	}
+	return exportPath(e.ID), e, nil
+}
+
+// PurgeExports deletes the archives whose link has expired and returns how
+// many were removed. Exports left pending or running by a restart are failed.
+func PurgeExports() (int, error) {
+	mdb, err := dbSession()
+	if err != nil {
+		return 0, err
+	}
+	defer mdb.Close()
+	col := mdb.DB(conf().DB.Name).C(tableName("export"))
+	if err := failStaleExports(col); err != nil {
+		return 0, err
+	}
+
+	expired := []models.Export{}
+	err = col.Find(bson.M{"status": ExportReady, "expires_at": bson.M{"$lte": time.Now()}}).All(&expired)
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range expired {
+		if err := os.Remove(exportPath(e.ID)); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+		if err := col.UpdateId(e.ID, bson.M{"$set": bson.M{"status": ExportExpired}}); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(expired), nil
+}
+
+func exportDir() string {
+	if conf().Export.Dir != "" {
+		return conf().Export.Dir
+	}
+	return filepath.Join(os.TempDir(), "vibe-export")
+}
+
+func exportPath(id bson.ObjectId) string {
+	return filepath.Join(exportDir(), id.Hex()+".zip")
+}
+
+func exportLinkTTL() time.Duration {
+	if conf().Export.LinkTTL > 0 {
+		return conf().Export.LinkTTL * time.Hour
+	}
+	return DefaultExportLinkTTL
+}
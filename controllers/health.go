@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// HealthCheck fails with the reason a dependency of Vibe cannot be used.
+type HealthCheck func() error
+
+// The status of a check and of the readiness as a whole.
+const (
+	CheckOK     = "ok"
+	CheckFailed = "failed"
+)
+
+// CheckResult is the outcome of one readiness check.
+type CheckResult struct {
+	Name    string  `json:"name"`
+	Status  string  `json:"status"`
+	Latency float64 `json:"latency_ms"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Readiness is the outcome of every check, ok when all of them passed.
+type Readiness struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+var (
+	// HealthCheckTimeout fails a check still running after it.
+	HealthCheckTimeout = 5 * time.Second
+
+	healthMu     sync.RWMutex
+	healthNames  = []string{"database", "log_directory", "signing_key"}
+	healthChecks = map[string]HealthCheck{
+		"database":      pingDatabase,
+		"log_directory": checkLogDirectories,
+		"signing_key":   checkSigningKey,
+	}
+)
+
+// RegisterHealthCheck adds a readiness check, or replaces the one of the same
+// name, such as a check of the mail relay of the application.
+func RegisterHealthCheck(name string, check HealthCheck) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	if _, ok := healthChecks[name]; !ok {
+		healthNames = append(healthNames, name)
+	}
+	healthChecks[name] = check
+}
+
+// UnregisterHealthCheck removes the readiness check of that name, if any.
+func UnregisterHealthCheck(name string) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	if _, ok := healthChecks[name]; !ok {
+		return
+	}
+	delete(healthChecks, name)
+	for i, n := range healthNames {
+		if n == name {
+			healthNames = append(healthNames[:i:i], healthNames[i+1:]...)
+			break
+		}
+	}
+}
+
+// Ready runs every readiness check at once: the database answers a ping, the
+// log directories are writable and the signing key signs.
+func Ready() *Readiness {
+	healthMu.RLock()
+	names := append([]string{}, healthNames...)
+	checks := make([]HealthCheck, len(names))
+	for i, name := range names {
+		checks[i] = healthChecks[name]
+	}
+	healthMu.RUnlock()
+
+	r := &Readiness{Status: CheckOK, Checks: make([]CheckResult, len(names))}
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r.Checks[i] = runCheck(names[i], checks[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, c := range r.Checks {
+		if c.Status != CheckOK {
+			r.Status = CheckFailed
+		}
+	}
+	return r
+}
+
+func runCheck(name string, check HealthCheck) CheckResult {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check()
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(HealthCheckTimeout):
+		err = fmt.Errorf("no answer within %s", HealthCheckTimeout)
+	}
+
+	res := CheckResult{
+		Name:    name,
+		Status:  CheckOK,
+		Latency: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Status, res.Error = CheckFailed, err.Error()
+	}
+	return res
+}
+
+func pingDatabase() error {
+	mdb, err := dbSessionWithin(HealthCheckTimeout)
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+
+	mdb.SetSyncTimeout(HealthCheckTimeout)
+	mdb.SetSocketTimeout(HealthCheckTimeout)
+	return mdb.Ping()
+}
+
+// checkLogDirectories creates and removes a file in the directory of every
+// configured log file, creating the directory as the logger would.
+func checkLogDirectories() error {
+	l := conf().Logger
+	seen := map[string]bool{}
+	for _, path := range []string{l.Debug, l.Info, l.Warn, l.Error, l.Fatal, l.Panic} {
+		dir := filepath.Dir(path)
+		if path == "" || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		if err := os.MkdirAll(dir, 0711); err != nil {
+			return err
+		}
+		f, err := ioutil.TempFile(dir, ".vibe-ready-")
+		if err != nil {
+			return err
+		}
+		f.Close()
+		os.Remove(f.Name())
+	}
+	return nil
+}
+
+// checkSigningKey signs a token with the configured key and method, which
+// sign the tokens and the export links.
+func checkSigningKey() error {
+	key, method := conf().JWT.SigningKey, conf().JWT.SigningMethod
+	if key == "" {
+		return errors.New("jwt.signingkey is not set")
+	}
+	m := jwt.GetSigningMethod(method)
+	if m == nil {
+		return fmt.Errorf("unknown signing method %q", method)
+	}
+	_, err := jwt.NewWithClaims(m, jwt.MapClaims{}).SignedString([]byte(key))
+	return err
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"github.com/dgrijalva/jwt-go"
+	"runtime"
+	"sync"
+	"time"
+)
+
+type (
+	// UserHook runs around the creation or deletion of u. A before-hook may
+	// change u, returning an error vetoes the operation.
+	UserHook func(u *User) error
+
+	// UpdateHook runs around an update. before is the stored account and
+	// after the merged account about to be stored, which a before-hook may
+	// change.
+	UpdateHook func(before, after *User) error
+
+	// TokenHook runs before a token is signed for u and may add or change
+	// claims. Returning an error refuses the token.
+	TokenHook func(u *User, claims jwt.MapClaims) error
+
+	// HookRegistry holds the lifecycle callbacks of embedding applications.
+	// Hooks run in registration order.
+	HookRegistry struct {
+		mu           sync.RWMutex
+		beforeCreate []UserHook
+		afterCreate  []UserHook
+		beforeUpdate []UpdateHook
+		afterUpdate  []UpdateHook
+		beforeDelete []UserHook
+		afterDelete  []UserHook
+		onTokenIssue []TokenHook
+	}
+)
+
+// Hooks is the registry consulted by User operations.
+var Hooks = new(HookRegistry)
+
+func (r *HookRegistry) BeforeCreate(fn UserHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.beforeCreate = append(r.beforeCreate, fn)
+}
+
+func (r *HookRegistry) AfterCreate(fn UserHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.afterCreate = append(r.afterCreate, fn)
+}
+
+func (r *HookRegistry) BeforeUpdate(fn UpdateHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.beforeUpdate = append(r.beforeUpdate, fn)
+}
+
+func (r *HookRegistry) AfterUpdate(fn UpdateHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.afterUpdate = append(r.afterUpdate, fn)
+}
+
+func (r *HookRegistry) BeforeDelete(fn UserHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.beforeDelete = append(r.beforeDelete, fn)
+}
+
+func (r *HookRegistry) AfterDelete(fn UserHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.afterDelete = append(r.afterDelete, fn)
+}
+
+func (r *HookRegistry) OnTokenIssue(fn TokenHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onTokenIssue = append(r.onTokenIssue, fn)
+}
+
+// Reset drops every registered hook.
+func (r *HookRegistry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.beforeCreate, r.afterCreate = nil, nil
+	r.beforeUpdate, r.afterUpdate = nil, nil
+	r.beforeDelete, r.afterDelete = nil, nil
+	r.onTokenIssue = nil
+}
+
+// runBefore stops at the first hook that vetoes.
+func (r *HookRegistry) runBefore(hooks *[]UserHook, u *User) error {
+	r.mu.RLock()
+	list := *hooks
+	r.mu.RUnlock()
+
+	for _, fn := range list {
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *HookRegistry) runBeforeUpdate(before, after *User) error {
+	r.mu.RLock()
+	list := r.beforeUpdate
+	r.mu.RUnlock()
+
+	for _, fn := range list {
+		if err := fn(before, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *HookRegistry) runToken(u *User, claims jwt.MapClaims) error {
+	r.mu.RLock()
+	list := r.onTokenIssue
+	r.mu.RUnlock()
+
+	for _, fn := range list {
+		if err := fn(u, claims); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runAfter runs every hook, the operation is done so failures are logged.
+func (r *HookRegistry) runAfter(hooks *[]UserHook, u *User) {
+	r.mu.RLock()
+	list := *hooks
+	r.mu.RUnlock()
+
+	for _, fn := range list {
+		if err := fn(u); err != nil {
+			hookFailed(err)
+		}
+	}
+}
+
+func (r *HookRegistry) runAfterUpdate(before, after *User) {
+	r.mu.RLock()
+	list := r.afterUpdate
+	r.mu.RUnlock()
+
+	for _, fn := range list {
+		if err := fn(before, after); err != nil {
+			hookFailed(err)
+		}
+	}
+}
+
+func hookFailed(err error) {
+	_, filename, _, _ := runtime.Caller(2)
+	logger.Error(map[string]interface{}{
+		"from":    filename,
+		"section": "Hook",
+		"time":    time.Now(),
+	}, err.Error())
+}
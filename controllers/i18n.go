@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"github.com/Festum/Vibe/utils"
+	"sync"
+	"time"
+)
+
+// Notification is an email or text message rendered for a user. SMS have no
+// subject.
+type Notification struct {
+	Lang    string
+	Subject string
+	Body    string
+}
+
+var (
+	messages     *utils.Catalog
+	messagesOnce sync.Once
+
+	// defaultMessages are the English texts, locale files translate them
+	// and may override them.
+	defaultMessages = map[string]string{
+		// Errors of a request
+		"BAD_REQUEST":                   "The request is malformed.",
+		"UNAUTHORIZED":                  "Authentication is required.",
+		"FORBIDDEN":                     "You are not allowed to do this.",
+		"NOT_FOUND":                     "Not found.",
+		"METHOD_NOT_ALLOWED":            "This method is not allowed here.",
+		"CONFLICT":                      "The request conflicts with the current state.",
+		"GONE":                          "This is no longer available.",
+		"UNSUPPORTED_MEDIA_TYPE":        "This content type is not supported.",
+		"INTERNAL_ERROR":                "Something went wrong on our side, please try again later.",
+		"SERVICE_UNAVAILABLE":           "The service is not available, please try again later.",
+		"ACCOUNT_EXISTS":                "An account with this username, email or phone already exists.",
+		"VALIDATION_FAILED":             "Some fields are invalid.",
+		"VERSION_CONFLICT":              "The account was changed meanwhile, reload it and try again.",
+		"PRECONDITION_REQUIRED":         "The If-Match header is required.",
+		"FORBIDDEN_WHILE_IMPERSONATING": "This cannot be done while impersonating.",
+		"CERTIFICATE_NOT_ALLOWED":       "A client certificate of a bot or api account is required.",
+		"ACCOUNT_REQUIRED":              "The account is required.",
+		"REASON_REQUIRED":               "A reason is required.",
+		"BAD_PATCH":                     "The patch is malformed.",
+		"PATH_NOT_FOUND":                "The patch refers to a field that does not exist.",
+		"PATCH_TEST_FAILED":             "A test of the patch failed.",
+		"UNSUPPORTED_PATCH_TYPE":        "This patch format is not supported.",
+		"BAD_CURSOR":                    "The page cursor is invalid.",
+		"BAD_SORT_FIELD":                "The list cannot be sorted by this field.",
+		"BAD_SEARCH_FIELD":              "The list cannot be searched by this field.",
+		"BAD_SIGNATURE":                 "The link is invalid.",
+		"LINK_EXPIRED":                  "The link has expired.",
+		"IMAGE_TOO_LARGE":               "The image is too large.",
+		"UNSUPPORTED_IMAGE":             "Only JPEG, PNG and GIF images are supported.",
+		"BAD_IMAGE":                     "The image cannot be read.",
+		"GATEWAY_NOT_CONFIGURED":        "Payments are not available.",
+		"CARD_DECLINED":                 "The card was declined.",
+		"ALREADY_ANONYMIZED":            "The account is already anonymized.",
+		"WEBHOOK_NOT_FOUND":             "The webhook does not exist.",
+		"DELIVERY_NOT_FOUND":            "No failed delivery has this id.",
+		"WELCOME":                       "Welcome {{.Username}}!",
+
+		// Reasons of an invalid field
+		"REQUIRED":               "is required",
+		"INVALID":                "is not valid",
+		"INVALID_EMAIL":          "must be a valid email address",
+		"INVALID_URL":            "must be a URL",
+		"NOT_ALPHANUMERIC":       "must contain only letters and digits",
+		"INVALID_USERNAME":       "must be 2 to 64 letters, digits, dots, dashes or underscores",
+		"UNKNOWN_ROLE":           "is not a known role",
+		"FIELD_NOT_PATCHABLE":    "field cannot be patched",
+		"TOO_LONG":               "is too long{{if .Max}}, at most {{.Max}} characters{{end}}",
+		"TOO_SHORT":              "must be at least {{.Min}} characters",
+		"TYPE_DATE":              "must be an RFC 3339 date",
+		"TYPE_INTEGER":           "must be an integer",
+		"TYPE_BOOLEAN":           "must be a boolean",
+		"TYPE_OBJECT":            "must be an object of strings",
+		"TYPE_STRING":            "must be a string",
+		"INVALID_LANGUAGE":       "must be a language tag such as en-US",
+		"IN_FUTURE":              "cannot be in the future",
+		"OUT_OF_RANGE":           "must be between {{.Min}} and {{.Max}}",
+		"UNKNOWN_COUNTRY":        "must be an ISO 3166 country code{{if .Suggestion}}, such as {{.Suggestion}}{{end}}",
+		"BAD_PHONE":              "must be a phone number",
+		"PHONE_REGION_REQUIRED":  "must start with + and the country calling code when no country is set",
+		"UNKNOWN_CALLING_CODE":   "has an unknown country calling code",
+		"PHONE_TOO_SHORT":        "is too short",
+		"PHONE_TOO_LONG":         "is too long",
+		"PHONE_TYPE_NOT_ALLOWED": "must be a number of type {{.Types}}",
+		"INVALID_CARD_NUMBER":    "is not a valid card number",
+		"UNSUPPORTED_CARD":       "is not a supported card",
+		"CARD_EXPIRED":           "card has expired",
+		"BAD_EXPIRY":             "must be MMYY",
+		"BAD_CID":                "must be {{.Digits}} digits",
+
+		// Notifications, by name and part
+		"email.welcome.subject":         "Welcome to {{.App}}",
+		"email.welcome.body":            "Hi {{.Name}},\n\nyour account {{.Username}} is ready.\n",
+		"email.export_ready.subject":    "Your data export is ready",
+		"email.export_ready.body":       "Hi {{.Name}},\n\nthe archive of your data can be downloaded until {{.Expires}}:\n{{.URL}}\n",
+		"email.account_deleted.subject": "Your account was deleted",
+		"email.account_deleted.body":    "Hi {{.Name}},\n\nyour account {{.Username}} was deleted. It can be restored until {{.PurgeAt}}, after that every data is erased.\n",
+		"sms.verification.body":         "{{.Code}} is your {{.App}} verification code.",
+	}
+)
+
+// catalog returns the texts of Vibe, the English defaults completed by the
+// locale files of the [i18n] section.
+func catalog() *utils.Catalog {
+	messagesOnce.Do(func() {
+		c := &utils.Catalog{Default: "en"}
+		c.Add("en", defaultMessages)
+		if conf().I18n.Dir != "" {
+			if err := c.LoadDir(conf().I18n.Dir); err != nil {
+				logger.Error(map[string]interface{}{
+					"section": "I18n",
+					"dir":     conf().I18n.Dir,
+					"time":    time.Now(),
+				}, err.Error())
+			}
+		}
+		if conf().I18n.Default != "" {
+			c.Default = conf().I18n.Default
+		}
+		messages = c
+	})
+	return messages
+}
+
+// Locale picks the language of a response among the catalog languages, from
+// preferences in order such as the Language of the user, then the
+// Accept-Language header.
+func Locale(prefs ...string) string {
+	return catalog().Match(prefs...)
+}
+
+// Localize returns the text of a message code in lang. Unknown codes are
+// returned as they are.
+func Localize(lang, code string, params map[string]interface{}) string {
+	if lang == "" {
+		lang = Locale()
+	}
+	return catalog().Text(lang, code, params)
+}
+
+// HasMessage tells if code is a message code of the catalog.
+func HasMessage(code string) bool {
+	return catalog().Has(code)
+}
+
+// Languages returns the languages texts are available in, the default first.
+func Languages() []string {
+	return catalog().Languages()
+}
+
+// MissingMessages lists the texts lang has no translation for.
+func MissingMessages(lang string) []string {
+	return catalog().Missing(lang)
+}
+
+// Notification renders the email or SMS name, such as "email.welcome", in
+// the language of u. App, Name and Username are set in data unless given.
+func (u *User) Notification(name string, data map[string]interface{}) (*Notification, error) {
+	values := map[string]interface{}{
+		"App":      conf().Title,
+		"Name":     u.DisplayName,
+		"Username": u.Username,
+	}
+	if u.DisplayName == "" {
+		values["Name"] = u.Username
+	}
+	for k, v := range data {
+		values[k] = v
+	}
+
+	n := &Notification{Lang: Locale(u.Language)}
+	body, err := catalog().Render(n.Lang, name+".body", values)
+	if err != nil {
+		if err.Error() == "MESSAGE_NOT_FOUND" {
+			return nil, newError(ErrNotFound, "UNKNOWN_NOTIFICATION")
+		}
+		return nil, err
+	}
+	n.Body = body
+	if subject, err := catalog().Render(n.Lang, name+".subject", values); err == nil {
+		n.Subject = subject
+	}
+	return n, nil
+}
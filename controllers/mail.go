@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"runtime"
+	"time"
+)
+
+// Mailer delivers the notifications of users, by email or any other channel
+// the application has. name is the rendered template, such as
+// "email.welcome".
+type Mailer interface {
+	Send(u *User, name string, n *Notification) error
+}
+
+// Mail delivers the welcome, export and deletion notifications. Nothing is
+// sent until the application sets it.
+var Mail Mailer
+
+// Notify renders the notification name for u and hands it to Mail. Failures
+// are logged, a notification never fails the operation it follows.
+func Notify(u *User, name string, data map[string]interface{}) {
+	if Mail == nil {
+		return
+	}
+
+	n, err := u.Notification(name, data)
+	if err == nil {
+		err = Mail.Send(u, name, n)
+	}
+	if err != nil {
+		_, filename, _, _ := runtime.Caller(1)
+		logger.Error(map[string]interface{}{
+			"from":         filename,
+			"section":      "Mail",
+			"notification": name,
+			"user":         u.Username,
+			"time":         time.Now(),
+		}, err.Error())
+	}
+}
@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"encoding/json"
+	"github.com/Festum/Vibe/utils"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+var (
+	// patchFields lists the JSON names of the fields a patch may touch and
+	// whether changing them is reserved to administrators.
+	patchFields = map[string]bool{
+		"display_name": false,
+		"given_name":   false,
+		"family_name":  false,
+		"language":     false,
+		"short_bio":    false,
+		"long_bio":     false,
+		"country":      false,
+		"phone":        false,
+		"birth":        false,
+		"age":          false,
+		"gender":       false,
+		"social":       false,
+		"email":        true,
+		"role":         true,
+		"is_disabled":  true,
+	}
+)
+
+// Patch applies a JSON Merge Patch or a JSON Patch document, chosen by its
+// content type, to the account of u. Only allowlisted fields may change and
+// asAdmin unlocks the ones reserved to administrators. Every invalid field is
+// reported in a *ValidationError. A non-zero u.Version must match the stored
+// version.
+func (u *User) Patch(contentType string, patch []byte, asAdmin bool) error {
+	return u.patch(contentType, patch, asAdmin, "")
+}
+
+// UpdateFields stores the fields of u named in fields, the JSON members of an
+// update request, as a merge patch of the account found by u.Username: other
+// fields keep their stored value and an empty string clears a field. The
+// allowlist of Patch applies, and a non-empty Password replaces the password.
+func (u *User) UpdateFields(fields []string, asAdmin bool) error {
+	values, err := new(utils.Marshal).S2M(u)
+	if err != nil {
+		return err
+	}
+	doc := map[string]interface{}{}
+	for _, f := range fields {
+		if _, ok := patchFields[f]; ok {
+			doc[f] = values[f]
+		}
+	}
+	patch, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	// The body may carry a new email, the account is found by its username.
+	target := &User{Username: u.Username, Version: u.Version, Actor: u.Actor}
+	if err := target.patch(MergePatchType, patch, asAdmin, u.Password); err != nil {
+		return err
+	}
+	*u = *target
+	return nil
+}
+
+func (u *User) patch(contentType string, patch []byte, asAdmin bool, password string) error {
+	current := User{ID: u.ID, Email: u.Email, Username: u.Username}
+	if err := current.Get(); err != nil {
+		return err
+	}
+	if u.Version != 0 && u.Version != current.Version {
+		return ErrVersionConflict
+	}
+
+	m := new(utils.Marshal)
+	full, err := m.S2M(&current)
+	if err != nil {
+		return err
+	}
+	doc := map[string]interface{}{}
+	for f := range patchFields {
+		if v, ok := full[f]; ok {
+			doc[f] = v
+		}
+	}
+
+	p := new(utils.Patch)
+	var result interface{}
+	switch contentType {
+	case MergePatchType:
+		result, err = p.Merge(doc, patch)
+	case JSONPatchType:
+		if err := checkPatchPaths(patch); err != nil {
+			return err
+		}
+		result, err = p.Apply(doc, patch)
+	default:
+		return newError(ErrBadRequest, "UNSUPPORTED_PATCH_TYPE")
+	}
+	if err != nil {
+		return typed(err)
+	}
+	patched, ok := result.(map[string]interface{})
+	if !ok {
+		return newError(ErrBadRequest, "BAD_PATCH")
+	}
+
+	verr := new(ValidationError)
+	for f := range patched {
+		if _, ok := patchFields[f]; !ok {
+			verr.Add(f, "FIELD_NOT_PATCHABLE")
+		}
+	}
+	for f, adminOnly := range patchFields {
+		if adminOnly && !asAdmin && !reflect.DeepEqual(doc[f], patched[f]) {
+			return ErrForbidden
+		}
+	}
+
+	next := current
+	decodePatched(&next, patched, verr)
+	next.Password = password
+	if verr.Err() == nil {
+		if err := next.validate(OpUpdate, &current, verr); err != nil {
+			return err
+		}
+		// Values stored before validation existed are left alone unless
+		// changed.
+		if next.Country != current.Country {
+			next.normalizeCountry(verr)
+		}
+		if next.Phone != current.Phone {
+			next.normalizePhone(verr)
+		}
+	}
+	if err := verr.Err(); err != nil {
+		return err
+	}
+	if password != "" {
+		if next.EncryptedPassword, next.Salt, err = new(utils.SaltAuth).Gen(password); err != nil {
+			return err
+		}
+	}
+
+	if err := u.save(&current, &next); err != nil {
+		return err
+	}
+
+	u.ID = current.ID
+	return u.Get()
+}
+
+// checkPatchPaths refuses JSON Patch operations outside the allowlist before
+// anything is applied.
+func checkPatchPaths(patch []byte) error {
+	paths, err := new(utils.Patch).Paths(patch)
+	if err != nil {
+		return typed(err)
+	}
+
+	verr := new(ValidationError)
+	for _, path := range paths {
+		field := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+		if _, ok := patchFields[field]; !ok || path == "" {
+			verr.Add(path, "FIELD_NOT_PATCHABLE")
+		}
+	}
+	return verr.Err()
+}
+
+// decodePatched resets every patchable field of u and decodes the patched
+// values into them, so removed members end up cleared.
+func decodePatched(u *User, patched map[string]interface{}, verr *ValidationError) {
+	v := reflect.ValueOf(u).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if _, ok := patchFields[name]; !ok {
+			continue
+		}
+
+		field := v.Field(i)
+		field.Set(reflect.Zero(field.Type()))
+		value, ok := patched[name]
+		if !ok || value == nil {
+			continue
+		}
+
+		raw, _ := json.Marshal(value)
+		if err := json.Unmarshal(raw, field.Addr().Interface()); err != nil {
+			verr.Add(name, typeCode(field.Type()))
+		}
+	}
+}
+
+func typeCode(t reflect.Type) string {
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return "TYPE_DATE"
+	case t.Kind() == reflect.Int64:
+		return "TYPE_INTEGER"
+	case t.Kind() == reflect.Bool:
+		return "TYPE_BOOLEAN"
+	case t.Kind() == reflect.Map:
+		return "TYPE_OBJECT"
+	}
+	return "TYPE_STRING"
+}
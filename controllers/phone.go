@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/Festum/Vibe/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"strings"
+	"sync"
+	"time"
+)
+
+var phoneIndexOnce sync.Once
+
+func phoneParser() *utils.Phone {
+	return &utils.Phone{Types: conf().Phone.Types}
+}
+
+// normalizePhone stores the phone of u in E.164, reading national numbers
+// as numbers of its country.
+func (u *User) normalizePhone(verr *ValidationError) {
+	if u.Phone == "" {
+		return
+	}
+	n, err := phoneParser().Parse(u.Phone, u.Country)
+	if err != nil {
+		verr.AddParams("phone", err.Error(), map[string]interface{}{
+			"Types": strings.Join(conf().Phone.Types, ", "),
+		})
+		return
+	}
+	u.Phone = n.E164
+}
+
+// phoneKey is the field the unique index of phones is kept on: the blind
+// index when phones are encrypted, the phone itself otherwise.
+func phoneKey() string {
+	if k, _ := activeKeyring(); k != nil {
+		return "phone_bidx"
+	}
+	return "phone"
+}
+
+// ensurePhoneIndex creates the unique phone index of the [phone] section.
+// Accounts without a phone do not store the field, the index is sparse.
+// Existing duplicates make it fail: they are logged and the service keeps
+// running without the index until "vibecli user phones" is run.
+func ensurePhoneIndex(col *mgo.Collection) {
+	if !conf().Phone.Unique {
+		return
+	}
+	phoneIndexOnce.Do(func() {
+		err := col.EnsureIndex(mgo.Index{
+			Key:        []string{phoneKey()},
+			Unique:     true,
+			Background: true,
+			Sparse:     true,
+		})
+		if err != nil {
+			logger.Error(map[string]interface{}{
+				"section": "PhoneIndex",
+				"time":    time.Now(),
+			}, err.Error())
+		}
+	})
+}
+
+// dropEmptyPhone leaves the phone out of a stored document when there is
+// none, so the sparse unique index skips it.
+func dropEmptyPhone(doc map[string]interface{}) {
+	if doc["phone"] == "" {
+		delete(doc, "phone")
+	}
+}
+
+// NormalizePhones rewrites the phones stored before numbers were normalized
+// in E.164, as needed before turning the unique index on. It returns the
+// number of accounts changed and the usernames whose phone could not be
+// parsed or is already used by another account.
+func NormalizePhones(progress func(int)) (int, []string, error) {
+	mdb, err := dbSession()
+	if err != nil {
+		return 0, nil, err
+	}
+	defer mdb.Close()
+
+	_, table := getTable("user")
+	col := mdb.DB(conf().DB.Name).C(table)
+	if _, err := col.UpdateAll(bson.M{"phone": ""}, bson.M{"$unset": bson.M{"phone": ""}}); err != nil {
+		return 0, nil, err
+	}
+
+	changed, failed := 0, []string{}
+	iter := col.Find(bson.M{"phone": bson.M{"$exists": true}}).Iter()
+	doc := bson.M{}
+	for iter.Next(&doc) {
+		u := User{}
+		if err := openInto(doc, userSealed, &u); err != nil {
+			iter.Close()
+			return changed, failed, err
+		}
+		doc = bson.M{}
+
+		before := u.Phone
+		verr := new(ValidationError)
+		u.normalizePhone(verr)
+		if verr.Err() != nil {
+			failed = append(failed, u.Username)
+			continue
+		}
+		if u.Phone == before {
+			continue
+		}
+		if err := userCrud(&u, "update"); err != nil {
+			if errors.Is(err, ErrConflict) {
+				failed = append(failed, u.Username)
+				continue
+			}
+			iter.Close()
+			return changed, failed, err
+		}
+		changed++
+		if progress != nil {
+			progress(changed)
+		}
+	}
+	return changed, failed, iter.Close()
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultDeleteGrace   = 30 * 24 * time.Hour
+	DefaultPurgeInterval = time.Hour
+)
+
+var purgerOnce sync.Once
+
+// DeleteGrace is how long a deleted account stays restorable.
+func DeleteGrace() time.Duration {
+	if conf().Account.DeleteGrace > 0 {
+		return conf().Account.DeleteGrace * 24 * time.Hour
+	}
+	return DefaultDeleteGrace
+}
+
+// Restore brings a deleted account back before it is purged.
+func (u *User) Restore() error {
+	before := User{ID: u.ID, Email: u.Email, Username: u.Username}
+	if err := userCrud(&before, "deleted"); err != nil {
+		return err
+	}
+	after := before
+	if err := userCrud(&after, "restore"); err != nil {
+		return err
+	}
+	auditRecord(AuditRestore, u.Actor, before.Username, &before, &after)
+	EmitUserEvent(EventRestored, &after)
+
+	u.ID = before.ID
+	return u.Get()
+}
+
+// Purge removes a deleted account and its linked data for good, without
+// waiting for its purge date.
+func (u *User) Purge() error {
+	before := User{ID: u.ID, Email: u.Email, Username: u.Username}
+	if err := userCrud(&before, "deleted"); err != nil {
+		return err
+	}
+	if err := userCrud(&User{ID: before.ID}, "delete"); err != nil {
+		return err
+	}
+	if err := before.dropLinkedData(); err != nil {
+		return err
+	}
+	// The entry names the account only, a diff would keep its data.
+	auditRecord(AuditPurge, u.Actor, before.Username, nil, nil)
+	return nil
+}
+
+// PurgeDeleted removes every deleted account past its purge date, with its
+// linked data, and returns how many were removed.
+func PurgeDeleted() (int, error) {
+	mdb, err := dbSession()
+	if err != nil {
+		return 0, err
+	}
+	defer mdb.Close()
+
+	_, table := getTable("user")
+	col := mdb.DB(conf().DB.Name).C(table)
+	err = col.EnsureIndex(mgo.Index{Key: []string{"purge_at"}, Sparse: true})
+	if err != nil {
+		return 0, err
+	}
+
+	expired := []User{}
+	if err := col.Find(bson.M{"purge_at": bson.M{"$lte": time.Now()}}).All(&expired); err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i := range expired {
+		// Restored in the meantime accounts no longer match.
+		err := col.Remove(bson.M{"_id": expired[i].ID, "purge_at": bson.M{"$lte": time.Now()}})
+		if err == mgo.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return purged, err
+		}
+		if err := expired[i].dropLinkedData(); err != nil {
+			return purged, err
+		}
+		auditRecord(AuditPurge, nil, expired[i].Username, nil, nil)
+		purged++
+	}
+
+	return purged, nil
+}
+
+// dropLinkedData removes what is stored about a purged account besides its
+// document: avatar, payment method, status, social identities, exports and
+// webhook deliveries.
+func (u *User) dropLinkedData() error {
+	social, err := u.socialRecords()
+	if err != nil {
+		return err
+	}
+	u.dropAvatar()
+	if err := u.dropBilling(); err != nil {
+		return err
+	}
+
+	mdb, err := dbSession()
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+	db := mdb.DB(conf().DB.Name)
+
+	if err := db.C(tableName("status")).RemoveId(u.ID); err != nil && err != mgo.ErrNotFound {
+		return err
+	}
+	if err := removeSocial(db, social); err != nil {
+		return err
+	}
+	if err := removeExports(db, u.Username); err != nil {
+		return err
+	}
+	_, err = db.C(tableName("delivery")).RemoveAll(bson.M{"user_id": u.ID})
+	return err
+}
+
+// StartPurger runs PurgeDeleted, PurgeExports, PurgeDeliveries and the
+// inactivity policy of AnonymizeInactive in the background every interval, or every configured
+// PurgeInterval when interval is zero. Only the first call starts the purger.
+func StartPurger(interval time.Duration) {
+	if interval <= 0 {
+		interval = conf().Account.PurgeInterval * time.Minute
+	}
+	if interval <= 0 {
+		interval = DefaultPurgeInterval
+	}
+
+	purgerOnce.Do(func() {
+		go func() {
+			tick := time.NewTicker(interval)
+			defer tick.Stop()
+			for {
+				if _, err := PurgeDeleted(); err != nil {
+					purgeFailed(err)
+				}
+				if _, err := PurgeExports(); err != nil {
+					purgeFailed(err)
+				}
+				if _, err := PurgeDeliveries(); err != nil {
+					purgeFailed(err)
+				}
+				if _, err := AnonymizeInactive(conf().Account.InactiveYears); err != nil {
+					purgeFailed(err)
+				}
+				<-tick.C
+			}
+		}()
+	})
+}
+
+func purgeFailed(err error) {
+	logger.Error(map[string]interface{}{
+		"section": "Purge",
+		"time":    time.Now(),
+	}, err.Error())
+}
@@ -4,17 +4,13 @@ import (
 	b64 "encoding/base64"
 	"errors"
 	"fmt"
-	"github.com/asaskevich/govalidator"
 	"github.com/dgrijalva/jwt-go"
-	"github.com/fatih/structs"
 	uuid "github.com/satori/go.uuid"
 	"github.com/Festum/Vibe/models"
 	"github.com/Festum/Vibe/utils"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"os"
-	"reflect"
-	"strings"
 	"sync"
 	"time"
 )
@@ -24,40 +20,66 @@ type (
 )
 
 var (
-	conf        = models.Config{}.Init()
-	TokenTTL    = conf.JWT.TokenTTL * time.Hour
-	TokenLeeway = 1 * time.Minute
+	DefaultTokenTTL = 60 * time.Hour
+	TokenLeeway     = 1 * time.Minute
+
+	validRoles = []string{
+		"admin",
+		"member",
+		"guest",
+		"bot",
+		"api",
+	}
 
 	tokenCache   = make(map[string]string)
 	tokenCacheMu sync.Mutex
+
+	mgoSession   *mgo.Session
+	mgoDial      *mongoDial
+	mgoSessionMu sync.Mutex
 )
 
+// conf is the configuration in use, read on every use to follow reloads.
+func conf() *models.Config {
+	return models.Conf()
+}
+
+// TokenTTL is how long issued tokens stay valid.
+func TokenTTL() time.Duration {
+	if conf().JWT.TokenTTL > 0 {
+		return conf().JWT.TokenTTL * time.Hour
+	}
+	return DefaultTokenTTL
+}
+
 func (u *User) Create() error {
 	sa := new(utils.SaltAuth)
 	u.EncryptedPassword, u.Salt, _ = sa.Gen(u.Password)
 	u.CreatedAt, u.UpdatedAt, u.LastLogin = time.Now(), time.Now(), time.Now()
 	u.IsDisabled = false
-	govalidator.TagMap["role"] = govalidator.Validator(func(str string) bool {
-		valid_roles := []string{
-			"admin",
-			"member",
-			"guest",
-			"bot",
-			"api",
-		}
-		for _, r := range valid_roles {
-			if r == str {
-				return true
-			}
-		}
-		return false
-	})
+	u.Version = 1
 
-	_, err := govalidator.ValidateStruct(u)
-	if err != nil {
+	verr := new(ValidationError)
+	if err := u.validate(OpCreate, nil, verr); err != nil {
+		return err
+	}
+	u.normalizeCountry(verr)
+	u.normalizePhone(verr)
+	if err := verr.Err(); err != nil {
+		return err
+	}
+
+	if err := Hooks.runBefore(&Hooks.beforeCreate, u); err != nil {
 		return err
 	}
-	err = userCrud(u, "create")
+
+	err := userCrud(u, "create")
+	if err == nil {
+		auditRecord(AuditCreate, u.Actor, u.Username, nil, u)
+		EmitUserEvent(EventRegistered, u)
+		Hooks.runAfter(&Hooks.afterCreate, u)
+		Notify(u, "email.welcome", nil)
+	}
 
 	return err
 }
@@ -70,54 +92,81 @@ func (u *User) Get() error {
 	return nil
 }
 
+// Update stores every patchable field of u, for trusted callers such as the
+// CLI changing an account they loaded.
 func (u *User) Update() error {
-	orgUser := User{Username: u.Username}
-	if err := orgUser.Get(); err != nil {
-		return err
-	}
-
-	changed, changedFields := structs.Map(u), structs.Names(u)
-	s := reflect.ValueOf(&orgUser).Elem()
-
-	for _, chField := range changedFields {
-		// exported field
-		f := s.FieldByName(chField)
-		if f.IsValid() && f.CanSet() {
-			if fmt.Sprintf("%s", f.Type()) == "int64" {
-				f.SetInt(changed[chField].(int64))
-			}
-			if fmt.Sprintf("%s", f.Type()) == "string" && changed[chField].(string) != "" {
-				f.SetString(changed[chField].(string))
-			}
-			if fmt.Sprintf("%s", f.Type()) == "bool" {
-				f.SetBool(changed[chField].(bool))
-			}
-		}
-	}
-
-	if u.Role != "" && (u.Role != "admin" && u.Role != "member" && u.Role != "guest" && u.Role != "bot" && u.Role != "api") {
-		return errors.New("Role is incorrect")
+	fields := make([]string, 0, len(patchFields))
+	for f := range patchFields {
+		fields = append(fields, f)
 	}
+	return u.UpdateFields(fields, true)
+}
 
-	err := userCrud(&orgUser, "update")
-	if err != nil {
+// save stores after in place of before, with the hooks, audit entry and
+// webhooks of an update.
+func (u *User) save(before, after *User) error {
+	if err := Hooks.runBeforeUpdate(before, after); err != nil {
 		return err
 	}
 
-	if err := u.Get(); err != nil {
+	if err := userCrud(after, "update"); err != nil {
 		return err
 	}
+	auditRecord(auditUpdateAction(before, after), u.Actor, after.Username, before, after)
+	emitUpdateEvents(before, after)
+	Hooks.runAfterUpdate(before, after)
 
 	return nil
 }
 
+// Delete moves the account to the trash. It disappears from lookups and
+// logins at once but can be restored until it is purged, DeleteGrace later.
 func (u *User) Delete() error {
-	if err := userCrud(u, "delete"); err != nil {
+	before := User{ID: u.ID, Email: u.Email, Username: u.Username}
+	if err := before.Get(); err != nil {
+		return err
+	}
+	if err := Hooks.runBefore(&Hooks.beforeDelete, &before); err != nil {
+		return err
+	}
+	after := before
+	if err := userCrud(&after, "trash"); err != nil {
 		return err
 	}
+	auditRecord(AuditDelete, u.Actor, before.Username, &before, &after)
+	EmitUserEvent(EventDeleted, &after)
+	Hooks.runAfter(&Hooks.afterDelete, &before)
+	Notify(&after, "email.account_deleted", map[string]interface{}{"PurgeAt": after.PurgeAt})
 	return nil
 }
 
+func isValidRole(role string) bool {
+	for _, r := range validRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeCountry stores the country of u as an alpha-2 code. Alpha-3 and
+// numeric codes are accepted, names are refused with a suggestion.
+func (u *User) normalizeCountry(verr *ValidationError) {
+	if u.Country == "" {
+		return
+	}
+	c := new(utils.Country)
+	if info, ok := c.Lookup(u.Country); ok {
+		u.Country = info.Alpha2
+		return
+	}
+	params := map[string]interface{}{}
+	if info, ok := c.Match(u.Country); ok {
+		params["Suggestion"] = info.Alpha2
+	}
+	verr.AddParams("country", "UNKNOWN_COUNTRY", params)
+}
+
 func (u *User) IsPass(pw string) bool {
 	if err := u.Get(); err != nil {
 		return false
@@ -136,6 +185,9 @@ func (u *User) GenerateToken(username, privateKey string, ttl int) (string, erro
 	if err := u.Get(); err != nil { //fetch extra data by key to fullfill token fields
 		return "", err
 	}
+	if err := u.recordLogin(); err != nil {
+		return "", err
+	}
 
 	uniqKey := u.Email + username + u.Username // neglect privateKey, its always the same
 	signed, ok := tokenCache[uniqKey]
@@ -143,46 +195,32 @@ func (u *User) GenerateToken(username, privateKey string, ttl int) (string, erro
 		return signed, nil
 	}
 
-	tknID := uuid.NewV4()
-
 	// Identifies the expiration time after which the JWT MUST NOT be accepted
 	// for processing.
 	if ttl < 0 {
-		ttl = int(TokenTTL)
+		ttl = int(TokenTTL())
 	}
 
-	if privateKey == "" {
-		privateKey = conf.JWT.SigningKey
+	claims := u.newClaims(username, time.Duration(ttl))
+	if err := Hooks.runToken(u, claims); err != nil {
+		return "", err
 	}
 
-	// Implementers MAY provide for some small leeway, usually no more than
-	// a few minutes, to account for clock skew.
-	leeway := TokenLeeway
-
-	tkn := jwt.New(jwt.GetSigningMethod(conf.JWT.SigningMethod))
-	claims := tkn.Claims.(jwt.MapClaims)
-	claims["iss"] = u.Username                                                  // Issuer
-	claims["sub"] = username                                                    // Subject
-	claims["aud"] = u.Email                                                     // Audience
-	claims["exp"] = time.Now().UTC().Add(time.Duration(ttl)).Add(leeway).Unix() // Expiration Time
-	claims["nbf"] = time.Now().UTC().Add(-leeway).Unix()                        // Not Before
-	claims["iat"] = time.Now().UTC().Unix()                                     // Issued At
-	claims["jti"] = tknID.String()                                              // JWT ID
-	claims["role"] = u.Role
-
 	var err error
-	signed, err = tkn.SignedString([]byte(privateKey))
+	signed, err = u.signToken(claims, privateKey)
 	if err != nil {
-		return "", errors.New("Server error: Cannot generate a token")
+		return "", err
 	}
 
+	auditRecord(AuditToken, u.Actor, u.Username, nil, nil)
+
 	// cache our token
 	tokenCache[uniqKey] = signed
 
 	// cache invalidation, because we cache the token in tokenCache we need to
 	// invalidate it expiration time. This was handled usually within JWT, but
 	// now we have to do it manually for our own cache.
-	time.AfterFunc(TokenTTL-TokenLeeway, func() {
+	time.AfterFunc(TokenTTL()-TokenLeeway, func() {
 		tokenCacheMu.Lock()
 		defer tokenCacheMu.Unlock()
 
@@ -192,6 +230,57 @@ func (u *User) GenerateToken(username, privateKey string, ttl int) (string, erro
 	return signed, nil
 }
 
+// recordLogin stores the time of a login, the inactivity policy of
+// AnonymizeInactive goes by it. It is not a change of the account, the
+// version is left alone.
+func (u *User) recordLogin() error {
+	mdb, err := dbSession()
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+
+	now := time.Now()
+	_, table := getTable("user")
+	if err := mdb.DB(conf().DB.Name).C(table).UpdateId(u.ID, bson.M{"$set": bson.M{"last_login": now}}); err != nil {
+		return err
+	}
+	u.LastLogin = now
+	return nil
+}
+
+// newClaims returns the registered claims of a token issued for u.
+func (u *User) newClaims(subject string, ttl time.Duration) jwt.MapClaims {
+	// Implementers MAY provide for some small leeway, usually no more than
+	// a few minutes, to account for clock skew.
+	leeway := TokenLeeway
+
+	return jwt.MapClaims{
+		"iss":  u.Username,                                   // Issuer
+		"sub":  subject,                                      // Subject
+		"aud":  u.Email,                                      // Audience
+		"exp":  time.Now().UTC().Add(ttl).Add(leeway).Unix(), // Expiration Time
+		"nbf":  time.Now().UTC().Add(-leeway).Unix(),         // Not Before
+		"iat":  time.Now().UTC().Unix(),                      // Issued At
+		"jti":  uuid.NewV4().String(),                        // JWT ID
+		"role": u.Role,
+	}
+}
+
+func (u *User) signToken(claims jwt.MapClaims, privateKey string) (string, error) {
+	if privateKey == "" {
+		privateKey = conf().JWT.SigningKey
+	}
+
+	tkn := jwt.NewWithClaims(jwt.GetSigningMethod(conf().JWT.SigningMethod), claims)
+	signed, err := tkn.SignedString([]byte(privateKey))
+	if err != nil {
+		return "", errors.New("TOKEN_GENERATION_FAILED")
+	}
+
+	return signed, nil
+}
+
 func (u *User) ParseToken(ut interface{}) map[string]interface{} {
 	token := ut.(*jwt.Token)
 
@@ -215,34 +304,120 @@ func (u *User) ParseBase64Token(encToken string) map[string]interface{} {
 }
 
 type mongoConnectionDetails struct {
+	username string
 	password string
 	hostPort string
 }
 
 func mongoConnDetailsFromCfg() *mongoConnectionDetails {
-	password := ""
 	host := os.Getenv("MONGO_PORT_27017_TCP_ADDR")
 	port := os.Getenv("MONGO_PORT_27017_TCP_PORT")
 
 	return &mongoConnectionDetails{
-		password: password,
+		username: conf().DB.User,
+		password: conf().DB.Password,
 		hostPort: host + ":" + port,
 	}
 }
 
+// mongoDial is a dial of the shared session in progress, the callers
+// needing the session meanwhile wait for its outcome.
+type mongoDial struct {
+	done chan struct{}
+	err  error
+}
+
+// dbSession returns a copy of the shared mongo session. The session is dialed
+// on first use so every store call reuses the same connection pool.
+func dbSession() (*mgo.Session, error) {
+	return dbSessionWithin(0)
+}
+
+// dbSessionWithin is dbSession giving up after wait when the session is
+// still being dialed, 0 waits for the dial to end.
+func dbSessionWithin(wait time.Duration) (*mgo.Session, error) {
+	mgoSessionMu.Lock()
+	if mgoSession != nil {
+		s := mgoSession.Copy()
+		mgoSessionMu.Unlock()
+		return s, nil
+	}
+	// Dial once for every caller and without the lock, so the callers of a
+	// dialed session are not held up by a slow or unreachable server.
+	d := mgoDial
+	if d == nil {
+		d = &mongoDial{done: make(chan struct{})}
+		mgoDial = d
+		go d.run()
+	}
+	mgoSessionMu.Unlock()
+
+	if wait > 0 {
+		select {
+		case <-d.done:
+		case <-time.After(wait):
+			return nil, fmt.Errorf("no database connection within %s", wait)
+		}
+	} else {
+		<-d.done
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return dbSessionWithin(wait)
+}
+
+func (d *mongoDial) run() {
+	s, err := dialMongo()
+
+	mgoSessionMu.Lock()
+	if err == nil {
+		mgoSession = s
+	}
+	d.err, mgoDial = err, nil
+	mgoSessionMu.Unlock()
+	close(d.done)
+}
+
+func dialMongo() (*mgo.Session, error) {
+	details := mongoConnDetailsFromCfg()
+	info, err := mgo.ParseURL(details.hostPort)
+	if err != nil {
+		return nil, err
+	}
+	info.Timeout = 10 * time.Second
+	info.Username, info.Password = details.username, details.password
+	s, err := mgo.DialWithInfo(info)
+	if err != nil {
+		return nil, err
+	}
+	s.SetMode(mgo.Monotonic, true)
+	return s, nil
+}
+
+// CloseDB closes the shared mongo session and its pool, at shutdown once no
+// request uses it anymore. The next store call dials again.
+func CloseDB() {
+	mgoSessionMu.Lock()
+	defer mgoSessionMu.Unlock()
+
+	if mgoSession != nil {
+		mgoSession.Close()
+		mgoSession = nil
+	}
+}
+
 func userCrud(user *User, action string) error {
 
-	mdb, err := mgo.Dial(mongoConnDetailsFromCfg().hostPort)
+	mdb, err := dbSession()
 	if err != nil {
 		return err
 	}
-
-	mdb.SetMode(mgo.Monotonic, true)
 	defer mdb.Close()
 
 	_, table := getTable("user")
 
-	col := mdb.DB(conf.DB.Name).C(table)
+	col := mdb.DB(conf().DB.Name).C(table)
 	err = col.EnsureIndex(mgo.Index{
 		Key:        []string{"username"},
 		Unique:     true,
@@ -253,33 +428,74 @@ func userCrud(user *User, action string) error {
 	if err != nil {
 		return errors.New("Ensure Error: " + err.Error())
 	}
+	ensurePhoneIndex(col)
 
 	colQuerier := bson.M{}
-	if user.Email != "" {
+	if user.ID != "" {
+		colQuerier = bson.M{"_id": user.ID}
+	} else if user.Email != "" {
 		colQuerier = bson.M{"email": user.Email}
 	} else if user.Username != "" {
 		colQuerier = bson.M{"username": user.Username}
 	} else {
-		return errors.New("BAD_KEY_INDEX")
+		return newError(ErrBadRequest, "BAD_KEY_INDEX")
 	}
 
 	user.Password = ""
 	switch action {
 	case "create":
-		err = col.Insert(&user)
+		// Sealed values are bound to the id of their document.
+		if user.ID == "" {
+			user.ID = bson.NewObjectId()
+		}
+		doc, err := sealedDoc(user, userSealed)
+		if err != nil {
+			return err
+		}
+		dropEmptyPhone(doc)
+		err = col.Insert(doc)
+		if mgo.IsDup(err) {
+			return ErrAccountExists
+		}
 		if err != nil {
-			//E11000: conflict
-			return errors.New(strings.Fields(err.Error())[0])
+			return err
 		}
-		err = col.Find(colQuerier).Sort("-timestamp").One(&user)
+		err = findOpen(col.Find(colQuerier).Sort("-timestamp"), userSealed, user)
 		if err != nil {
 			return errors.New("CHECK_CREATED_ACCOUNT_FAILED")
 		}
 	case "read":
-		err = col.Find(colQuerier).Sort("-timestamp").One(&user)
+		err = findOpen(col.Find(bson.M{"$and": []bson.M{colQuerier, liveQuerier}}).Sort("-timestamp"), userSealed, user)
 		if err != nil {
-			return err
+			return typed(err)
+		}
+	case "deleted":
+		err = findOpen(col.Find(bson.M{"$and": []bson.M{colQuerier, deletedQuerier}}), userSealed, user)
+		if err != nil {
+			return typed(err)
+		}
+	case "trash":
+		now := time.Now()
+		purge := now.Add(DeleteGrace())
+		err = col.Update(bson.M{"$and": []bson.M{colQuerier, liveQuerier}}, bson.M{
+			"$set": bson.M{"deleted_at": now, "purge_at": purge},
+			"$inc": bson.M{"version": 1},
+		})
+		if err != nil {
+			return typed(err)
+		}
+		user.DeletedAt, user.PurgeAt = &now, &purge
+		user.Version++
+	case "restore":
+		err = col.Update(bson.M{"$and": []bson.M{colQuerier, deletedQuerier}}, bson.M{
+			"$unset": bson.M{"deleted_at": "", "purge_at": ""},
+			"$inc":   bson.M{"version": 1},
+		})
+		if err != nil {
+			return typed(err)
 		}
+		user.DeletedAt, user.PurgeAt = nil, nil
+		user.Version++
 	case "update":
 		user.UpdatedAt = time.Now()
 
@@ -292,11 +508,45 @@ func userCrud(user *User, action string) error {
 		//Need to reset non-json fields
 		change["encrypted_password"] = user.EncryptedPassword
 		change["salt"] = user.Salt
+		delete(change, "id")
+		// Keep dates typed, queries compare them with dates.
+		for k, t := range map[string]time.Time{
+			"birth":      user.Birth,
+			"created_at": user.CreatedAt,
+			"updated_at": user.UpdatedAt,
+			"last_login": user.LastLogin,
+		} {
+			change[k] = t
+		}
+		for k, t := range map[string]*time.Time{
+			"deleted_at":    user.DeletedAt,
+			"purge_at":      user.PurgeAt,
+			"anonymized_at": user.AnonymizedAt,
+		} {
+			if t != nil {
+				change[k] = *t
+			}
+		}
 
-		err = col.Update(colQuerier, change)
+		dropEmptyPhone(change)
+		if err := sealDoc(change, user.ID, userSealed); err != nil {
+			return err
+		}
+
+		// Only replace the version that was read, so a concurrent writer
+		// makes this update fail instead of being overwritten.
+		change["version"] = user.Version + 1
+		err = col.Update(bson.M{"$and": []bson.M{colQuerier, versionQuerier(user.Version)}}, change)
+		if err == mgo.ErrNotFound {
+			return ErrVersionConflict
+		}
+		if mgo.IsDup(err) {
+			return ErrAccountExists
+		}
 		if err != nil {
 			return err
 		}
+		user.Version++
 	case "delete":
 		_, err = col.RemoveAll(colQuerier)
 		if err != nil {
@@ -306,6 +556,20 @@ func userCrud(user *User, action string) error {
 	return nil
 }
 
+var (
+	liveQuerier    = bson.M{"deleted_at": bson.M{"$exists": false}}
+	deletedQuerier = bson.M{"deleted_at": bson.M{"$exists": true}}
+)
+
+// versionQuerier matches a document at version v. Documents created before
+// versioning have no version field and count as version 0.
+func versionQuerier(v int64) bson.M {
+	if v == 0 {
+		return bson.M{"version": bson.M{"$in": []interface{}{0, nil}}}
+	}
+	return bson.M{"version": v}
+}
+
 func getTable(scene string) ([]string, string) {
 	key := []string{}
 	table := "user"
@@ -318,5 +582,14 @@ func getTable(scene string) ([]string, string) {
 		key = []string{"social"}
 	}
 
-	return key, conf.DB.Table[table]
+	return key, tableName(table)
+}
+
+// tableName resolves a collection name from the [database.table] section,
+// falling back to the scene name when it is not configured.
+func tableName(scene string) string {
+	if t, ok := conf().DB.Table[scene]; ok && t != "" {
+		return t
+	}
+	return scene
 }
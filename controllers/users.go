@@ -0,0 +1,307 @@
+package controllers
+
+import (
+	b64 "encoding/base64"
+	"errors"
+	"github.com/Festum/Vibe/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// UserQuery describes a filtered, sorted and cursor paginated listing of users
+// for administration tools. Zero values mean "no filter".
+type UserQuery struct {
+	Role         string
+	Country      string
+	Phone        string // exact match, through the blind index when phones are encrypted
+	Disabled     *bool
+	Deleted      bool // list the accounts in the trash instead of the live ones
+	CreatedAfter time.Time
+	CreatedUntil time.Time
+	LoginAfter   time.Time
+	LoginUntil   time.Time
+	Search       string            // prefix matched against username, email and display name
+	Prefix       map[string]string // prefix matched against a single searchable field
+	Sort         string            // sortable field, prefix with "-" for descending order
+	Cursor       string
+	Limit        int
+}
+
+type UserPage struct {
+	Users      []User `json:"users"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type userCursor struct {
+	Sort  string        `bson:"s"`
+	Value interface{}   `bson:"v"`
+	ID    bson.ObjectId `bson:"id"`
+}
+
+var (
+	listIndexOnce sync.Once
+
+	sortableFields = map[string]bool{
+		"_id":          true,
+		"username":     true,
+		"email":        true,
+		"display_name": true,
+		"created_at":   true,
+		"updated_at":   true,
+		"last_login":   true,
+	}
+	searchableFields = []string{"username", "email", "display_name"}
+)
+
+// Find runs the query and returns one page of users with the cursor of the
+// next page, which is empty when there are no more results.
+func (q *UserQuery) Find() (*UserPage, error) {
+	field, desc, err := q.sortField()
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := q.filter(field, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	mdb, err := dbSession()
+	if err != nil {
+		return nil, err
+	}
+	defer mdb.Close()
+
+	_, table := getTable("user")
+	col := mdb.DB(conf().DB.Name).C(table)
+	ensureListIndexes(col)
+
+	order := []string{field, "_id"}
+	if desc {
+		order = []string{"-" + field, "-_id"}
+	}
+	if field == "_id" {
+		order = order[1:]
+	}
+
+	page := &UserPage{Users: []User{}}
+	// Fetch one extra document to know whether another page exists.
+	docs := []bson.M{}
+	if err := col.Find(filter).Sort(order...).Limit(limit + 1).All(&docs); err != nil {
+		return nil, err
+	}
+	for _, doc := range docs {
+		u := User{}
+		if err := openInto(doc, userSealed, &u); err != nil {
+			return nil, err
+		}
+		page.Users = append(page.Users, u)
+	}
+
+	if len(page.Users) > limit {
+		page.Users = page.Users[:limit]
+		last := page.Users[limit-1]
+		page.NextCursor, err = encodeCursor(q.sortSpec(field, desc), &last, field)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// ensureListIndexes creates the indexes of the filters and sorts of listings
+// once per process. Failures are logged, listings work without them.
+func ensureListIndexes(col *mgo.Collection) {
+	listIndexOnce.Do(func() {
+		for _, key := range []string{"email", "display_name", "role", "country", "created_at", "last_login"} {
+			if err := col.EnsureIndexKey(key); err != nil {
+				logger.Error(map[string]interface{}{
+					"section": "ListIndex",
+					"key":     key,
+					"time":    time.Now(),
+				}, err.Error())
+			}
+		}
+	})
+}
+
+func (q *UserQuery) sortField() (string, bool, error) {
+	field, desc := strings.TrimSpace(q.Sort), false
+	if strings.HasPrefix(field, "-") {
+		field, desc = field[1:], true
+	}
+	if field == "" || field == "id" {
+		field = "_id"
+	}
+	if !sortableFields[field] {
+		return "", false, newError(ErrBadRequest, "BAD_SORT_FIELD")
+	}
+	return field, desc, nil
+}
+
+func (q *UserQuery) sortSpec(field string, desc bool) string {
+	if desc {
+		return "-" + field
+	}
+	return field
+}
+
+func (q *UserQuery) filter(field string, desc bool) (bson.M, error) {
+	conds := []bson.M{liveQuerier}
+	if q.Deleted {
+		conds = []bson.M{deletedQuerier}
+	}
+
+	if q.Role != "" {
+		conds = append(conds, bson.M{"role": q.Role})
+	}
+	if q.Country != "" {
+		country := q.Country
+		if info, ok := new(utils.Country).Lookup(country); ok {
+			country = info.Alpha2
+		}
+		conds = append(conds, bson.M{"country": country})
+	}
+	if q.Phone != "" {
+		if n, err := new(utils.Phone).Parse(q.Phone, q.Country); err == nil {
+			q.Phone = n.E164
+		}
+		k, err := activeKeyring()
+		if err != nil {
+			return nil, err
+		}
+		if k != nil {
+			conds = append(conds, bson.M{"phone_bidx": k.BlindIndex(q.Phone)})
+		} else {
+			conds = append(conds, bson.M{"phone": q.Phone})
+		}
+	}
+	if q.Disabled != nil {
+		conds = append(conds, bson.M{"is_disabled": *q.Disabled})
+	}
+	if r := timeRange(q.CreatedAfter, q.CreatedUntil); r != nil {
+		conds = append(conds, bson.M{"created_at": r})
+	}
+	if r := timeRange(q.LoginAfter, q.LoginUntil); r != nil {
+		conds = append(conds, bson.M{"last_login": r})
+	}
+	if q.Search != "" {
+		or := []bson.M{}
+		for _, f := range searchableFields {
+			or = append(or, bson.M{f: prefixRegex(q.Search)})
+		}
+		conds = append(conds, bson.M{"$or": or})
+	}
+	for f, v := range q.Prefix {
+		if !isSearchable(f) {
+			return nil, newError(ErrBadRequest, "BAD_SEARCH_FIELD")
+		}
+		if v != "" {
+			conds = append(conds, bson.M{f: prefixRegex(v)})
+		}
+	}
+
+	if q.Cursor != "" {
+		cur, err := decodeCursor(q.Cursor)
+		if err != nil || cur.Sort != q.sortSpec(field, desc) {
+			return nil, newError(ErrBadRequest, "BAD_CURSOR")
+		}
+		op := "$gt"
+		if desc {
+			op = "$lt"
+		}
+		if field == "_id" {
+			conds = append(conds, bson.M{"_id": bson.M{op: cur.ID}})
+		} else {
+			conds = append(conds, bson.M{"$or": []bson.M{
+				{field: bson.M{op: cur.Value}},
+				{field: cur.Value, "_id": bson.M{op: cur.ID}},
+			}})
+		}
+	}
+
+	if len(conds) == 1 {
+		return conds[0], nil
+	}
+	return bson.M{"$and": conds}, nil
+}
+
+func timeRange(from, until time.Time) bson.M {
+	r := bson.M{}
+	if !from.IsZero() {
+		r["$gte"] = from
+	}
+	if !until.IsZero() {
+		r["$lt"] = until
+	}
+	if len(r) == 0 {
+		return nil
+	}
+	return r
+}
+
+func prefixRegex(prefix string) bson.RegEx {
+	return bson.RegEx{Pattern: "^" + regexp.QuoteMeta(prefix), Options: "i"}
+}
+
+func isSearchable(field string) bool {
+	for _, f := range searchableFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeCursor captures the sort value and id of the last user of a page. The
+// value is kept as BSON so that dates and strings compare the same way on the
+// next request.
+func encodeCursor(sort string, last *User, field string) (string, error) {
+	raw, err := bson.Marshal(last)
+	if err != nil {
+		return "", err
+	}
+	doc := bson.M{}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return "", err
+	}
+
+	raw, err = bson.Marshal(userCursor{Sort: sort, Value: doc[field], ID: last.ID})
+	if err != nil {
+		return "", err
+	}
+	return b64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(s string) (*userCursor, error) {
+	raw, err := b64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	cur := new(userCursor)
+	if err := bson.Unmarshal(raw, cur); err != nil {
+		return nil, err
+	}
+	if !cur.ID.Valid() {
+		return nil, errors.New("BAD_CURSOR")
+	}
+	return cur, nil
+}
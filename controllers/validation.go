@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/asaskevich/govalidator"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// The operations validated by their own rule set.
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpLogin  = "login"
+)
+
+var (
+	// Rules are the default rules of every operation by JSON field name, a
+	// comma separated list such as "required,max=64". The
+	// [validation.<operation>] sections of the configuration replace them
+	// field by field, an empty list makes a field free.
+	Rules = defaultRules()
+
+	languageTag     = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{1,63}$`)
+)
+
+func defaultRules() map[string]map[string]string {
+	profile := map[string]string{
+		"display_name": "max=64",
+		"given_name":   "max=64",
+		"family_name":  "max=64",
+		"language":     "language",
+		"short_bio":    "max=160",
+		"long_bio":     "max=4096",
+		"phone":        "max=32",
+		"birth":        "past",
+		"age":          "range=0:150",
+		"gender":       "range=0:3",
+	}
+	rules := map[string]map[string]string{
+		OpCreate: {
+			"email":    "required,email,max=254",
+			"username": "required,username",
+			"password": "required,min=8,max=128",
+			"role":     "required,role",
+		},
+		OpUpdate: {
+			"email":    "required,email,max=254",
+			"username": "required,username",
+			"password": "min=8,max=128",
+			"role":     "required,role",
+		},
+		OpLogin: {
+			"password": "required,max=128",
+		},
+	}
+	for f, r := range profile {
+		rules[OpCreate][f] = r
+		rules[OpUpdate][f] = r
+	}
+	return rules
+}
+
+// rulesOf merges the configured rules of op over its defaults.
+func rulesOf(op string) (map[string]string, bool) {
+	defaults, ok := Rules[op]
+	configured, found := conf().Validation[op]
+	if !ok && !found {
+		return nil, false
+	}
+	rules := map[string]string{}
+	for f, r := range defaults {
+		rules[f] = r
+	}
+	for f, r := range configured {
+		rules[f] = r
+	}
+	return rules, true
+}
+
+// A rule refuses a value that is not empty with a message code and its
+// parameters, or accepts it with "". arg follows "=" in the rule, such as 64
+// in max=64.
+type rule func(v reflect.Value, arg string) (string, map[string]interface{}, error)
+
+var ruleFuncs = map[string]rule{
+	"email":    stringRule(govalidator.IsEmail, "INVALID_EMAIL"),
+	"alphanum": stringRule(govalidator.IsAlphanumeric, "NOT_ALPHANUMERIC"),
+	"username": stringRule(usernamePattern.MatchString, "INVALID_USERNAME"),
+	"role":     stringRule(isValidRole, "UNKNOWN_ROLE"),
+	"language": stringRule(languageTag.MatchString, "INVALID_LANGUAGE"),
+	"url": stringRule(func(s string) bool {
+		return strings.HasPrefix(s, "/") || govalidator.IsURL(s)
+	}, "INVALID_URL"),
+	"min": func(v reflect.Value, arg string) (string, map[string]interface{}, error) {
+		min, err := strconv.Atoi(arg)
+		if err != nil || v.Kind() != reflect.String {
+			return "", nil, errBadRule
+		}
+		if utf8.RuneCountInString(v.String()) < min {
+			return "TOO_SHORT", map[string]interface{}{"Min": min}, nil
+		}
+		return "", nil, nil
+	},
+	"max": func(v reflect.Value, arg string) (string, map[string]interface{}, error) {
+		max, err := strconv.Atoi(arg)
+		if err != nil || v.Kind() != reflect.String {
+			return "", nil, errBadRule
+		}
+		if utf8.RuneCountInString(v.String()) > max {
+			return "TOO_LONG", map[string]interface{}{"Max": max}, nil
+		}
+		return "", nil, nil
+	},
+	"range": func(v reflect.Value, arg string) (string, map[string]interface{}, error) {
+		bounds := strings.SplitN(arg, ":", 2)
+		if len(bounds) != 2 || v.Kind() != reflect.Int64 {
+			return "", nil, errBadRule
+		}
+		min, err := strconv.ParseInt(bounds[0], 10, 64)
+		if err != nil {
+			return "", nil, errBadRule
+		}
+		max, err := strconv.ParseInt(bounds[1], 10, 64)
+		if err != nil {
+			return "", nil, errBadRule
+		}
+		if n := v.Int(); n < min || n > max {
+			return "OUT_OF_RANGE", map[string]interface{}{"Min": min, "Max": max}, nil
+		}
+		return "", nil, nil
+	},
+	"past": func(v reflect.Value, arg string) (string, map[string]interface{}, error) {
+		t, ok := v.Interface().(time.Time)
+		if !ok {
+			return "", nil, errBadRule
+		}
+		if t.After(time.Now()) {
+			return "IN_FUTURE", nil, nil
+		}
+		return "", nil, nil
+	},
+}
+
+var errBadRule = errors.New("BAD_VALIDATION_RULE")
+
+func stringRule(valid func(string) bool, code string) rule {
+	return func(v reflect.Value, arg string) (string, map[string]interface{}, error) {
+		if v.Kind() != reflect.String {
+			return "", nil, errBadRule
+		}
+		if !valid(v.String()) {
+			return code, nil, nil
+		}
+		return "", nil, nil
+	}
+}
+
+// Validate checks u against the rules of op and reports every invalid field
+// in a *ValidationError. With before, only the fields changed since before
+// are checked, values stored before validation existed are left alone.
+func (u *User) Validate(op string, before *User) error {
+	verr := new(ValidationError)
+	if err := u.validate(op, before, verr); err != nil {
+		return err
+	}
+	return verr.Err()
+}
+
+// ValidateLogin checks the credentials of a login against the login rules.
+// One of the email and the username is required.
+func (u *User) ValidateLogin(password string) error {
+	verr := new(ValidationError)
+	if u.Email == "" && u.Username == "" {
+		verr.Add("username", "REQUIRED")
+	}
+	creds := User{Email: u.Email, Username: u.Username, Password: password}
+	if err := creds.validate(OpLogin, nil, verr); err != nil {
+		return err
+	}
+	return verr.Err()
+}
+
+// validate adds the invalid fields of u to verr. It fails on a rule that does
+// not exist or does not fit its field, a configuration error.
+func (u *User) validate(op string, before *User, verr *ValidationError) error {
+	fields, ok := rulesOf(op)
+	if !ok {
+		return errors.New("UNKNOWN_OPERATION: " + op)
+	}
+
+	v := reflect.ValueOf(u).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		list, ok := fields[name]
+		if !ok || list == "" {
+			continue
+		}
+		value := v.Field(i)
+		if before != nil && reflect.DeepEqual(value.Interface(), reflect.ValueOf(before).Elem().Field(i).Interface()) {
+			continue
+		}
+
+		if err := checkField(name, value, list, verr); err != nil {
+			return errors.New(err.Error() + ": " + op + "." + name + " = " + list)
+		}
+	}
+	return nil
+}
+
+// checkField applies a rule list to one value, an empty value is only
+// refused by required.
+func checkField(name string, value reflect.Value, list string, verr *ValidationError) error {
+	empty := isBlank(value)
+	for _, r := range strings.Split(list, ",") {
+		r = strings.TrimSpace(r)
+		if r == "required" {
+			if empty {
+				verr.Add(name, "REQUIRED")
+				return nil
+			}
+			continue
+		}
+		parts := strings.SplitN(r, "=", 2)
+		f, ok := ruleFuncs[parts[0]]
+		if !ok {
+			return errBadRule
+		}
+		if empty {
+			continue
+		}
+		arg := ""
+		if len(parts) == 2 {
+			arg = parts[1]
+		}
+		code, params, err := f(value, arg)
+		if err != nil {
+			return err
+		}
+		if code != "" {
+			verr.AddParams(name, code, params)
+			return nil
+		}
+	}
+	return nil
+}
+
+func isBlank(v reflect.Value) bool {
+	if v.Kind() == reflect.Map || v.Kind() == reflect.Slice {
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
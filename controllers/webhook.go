@@ -0,0 +1,505 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Festum/Vibe/models"
+	"github.com/Festum/Vibe/utils"
+	"github.com/asaskevich/govalidator"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	EventRegistered  = "user.registered"
+	EventUpdated     = "user.updated"
+	EventRoleChanged = "user.role_changed"
+	EventDisabled    = "user.disabled"
+	EventDeleted     = "user.deleted"
+	EventRestored    = "user.restored"
+	EventAnonymized  = "user.anonymized"
+	EventLogin       = "user.login"
+
+	DeliveryPending   = "pending"
+	DeliverySending   = "sending"
+	DeliveryRetrying  = "retrying"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+
+	DefaultWebhookAttempts   = 8
+	DefaultWebhookBackoff    = 30 * time.Second
+	DefaultWebhookTimeout    = 10 * time.Second
+	DefaultDeliveryRetention = 30 * 24 * time.Hour
+	maxWebhookBackoff        = 6 * time.Hour
+
+	SignatureHeader = "X-Vibe-Signature"
+	EventHeader     = "X-Vibe-Event"
+	DeliveryHeader  = "X-Vibe-Delivery"
+)
+
+var (
+	// WebhookClient sends the deliveries, replace it to tune transports. An
+	// attempt is bounded by webhook.timeout as configured when it is made.
+	WebhookClient = new(http.Client)
+
+	webhookWake = make(chan struct{}, 1)
+	webhookOnce sync.Once
+
+	errWebhookNotFound  = newError(ErrNotFound, "WEBHOOK_NOT_FOUND")
+	errDeliveryNotFound = newError(ErrNotFound, "DELIVERY_NOT_FOUND")
+)
+
+// DeliverWebhook makes one signed delivery attempt. It returns the response
+// status code and an error unless the subscriber answered with a 2xx status.
+func DeliverWebhook(hook models.Webhook, d *models.Delivery) (int, error) {
+	body := []byte(d.Payload)
+	req, err := http.NewRequest("POST", hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	signer := &utils.WebhookSigner{Secret: hook.Secret}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Vibe-Webhook/"+conf().Build)
+	req.Header.Set(SignatureHeader, signer.Sign(time.Now(), body))
+	req.Header.Set(EventHeader, d.Event)
+	req.Header.Set(DeliveryHeader, d.ID.Hex())
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout())
+	defer cancel()
+	resp, err := WebhookClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("subscriber answered %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// WebhookBackoff is the delay before the next attempt once attempts have
+// failed. It doubles from the configured base up to a few hours.
+func WebhookBackoff(attempts int) time.Duration {
+	delay := conf().Webhook.Backoff * time.Second
+	if delay <= 0 {
+		delay = DefaultWebhookBackoff
+	}
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxWebhookBackoff {
+			return maxWebhookBackoff
+		}
+	}
+	return delay
+}
+
+// EmitUserEvent queues the event for every subscriber interested in it. The
+// user is sent as it is returned by the API, secrets are never included.
+func EmitUserEvent(event string, u *User) {
+	if err := emit(event, u); err != nil {
+		_, filename, _, _ := runtime.Caller(1)
+		logger.Error(map[string]interface{}{
+			"from":    filename,
+			"section": "Webhook",
+			"event":   event,
+			"time":    time.Now(),
+		}, err.Error())
+	}
+}
+
+// emitUpdateEvents sends the events describing the change of an account.
+func emitUpdateEvents(before, after *User) {
+	EmitUserEvent(EventUpdated, after)
+	if before.Role != after.Role {
+		EmitUserEvent(EventRoleChanged, after)
+	}
+	if !before.IsDisabled && after.IsDisabled {
+		EmitUserEvent(EventDisabled, after)
+	}
+}
+
+func emit(event string, u *User) error {
+	hooks, err := Webhooks()
+	if err != nil {
+		return err
+	}
+
+	data := *u
+	data.Password, data.EncryptedPassword, data.Salt = "", "", ""
+	payload, err := json.Marshal(models.WebhookPayload{
+		ID:    uuid.NewV4().String(),
+		Event: event,
+		When:  time.Now().UTC(),
+		Data:  data,
+	})
+	if err != nil {
+		return err
+	}
+
+	mdb, err := dbSession()
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+
+	col := mdb.DB(conf().DB.Name).C(tableName("delivery"))
+	queued := 0
+	for _, hook := range hooks {
+		if !subscribed(hook, event) {
+			continue
+		}
+		d := &models.Delivery{
+			ID:          bson.NewObjectId(),
+			WebhookID:   hook.ID,
+			UserID:      u.ID,
+			URL:         hook.URL,
+			Event:       event,
+			Payload:     string(payload),
+			Status:      DeliveryPending,
+			NextAttempt: time.Now(),
+			CreatedAt:   time.Now(),
+		}
+		doc, err := sealedDoc(d, deliverySealed)
+		if err != nil {
+			return err
+		}
+		if err := col.Insert(doc); err != nil {
+			return err
+		}
+		queued++
+	}
+
+	if queued > 0 {
+		select {
+		case webhookWake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func subscribed(hook models.Webhook, event string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, e := range hook.Events {
+		if e == event || e == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhooks lists the subscriptions from the config followed by the ones
+// created through the admin API.
+func Webhooks() ([]models.Webhook, error) {
+	hooks := append([]models.Webhook{}, conf().Webhook.Endpoints...)
+
+	mdb, err := dbSession()
+	if err != nil {
+		return nil, err
+	}
+	defer mdb.Close()
+
+	stored := []models.Webhook{}
+	if err := mdb.DB(conf().DB.Name).C(tableName("webhook")).Find(nil).Sort("created_at").All(&stored); err != nil {
+		return nil, err
+	}
+
+	return append(hooks, stored...), nil
+}
+
+// AddWebhook stores a new subscription.
+func AddWebhook(hook *models.Webhook) error {
+	if _, err := govalidator.ValidateStruct(hook); err != nil {
+		return fromValidator(err, hook)
+	}
+
+	mdb, err := dbSession()
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+
+	hook.ID = bson.NewObjectId()
+	hook.CreatedAt = time.Now()
+
+	return mdb.DB(conf().DB.Name).C(tableName("webhook")).Insert(hook)
+}
+
+// RemoveWebhook deletes a subscription created through the admin API.
+func RemoveWebhook(id bson.ObjectId) error {
+	mdb, err := dbSession()
+	if err != nil {
+		return err
+	}
+	defer mdb.Close()
+
+	return typed(mdb.DB(conf().DB.Name).C(tableName("webhook")).RemoveId(id))
+}
+
+// webhookFor finds the subscription a delivery was queued for. Config
+// subscriptions have no id and are matched by URL.
+func webhookFor(d *models.Delivery) (models.Webhook, error) {
+	if d.WebhookID == "" {
+		for _, hook := range conf().Webhook.Endpoints {
+			if hook.URL == d.URL {
+				return hook, nil
+			}
+		}
+		return models.Webhook{}, errWebhookNotFound
+	}
+
+	mdb, err := dbSession()
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	defer mdb.Close()
+
+	hook := models.Webhook{}
+	if err := mdb.DB(conf().DB.Name).C(tableName("webhook")).FindId(d.WebhookID).One(&hook); err != nil {
+		if err == mgo.ErrNotFound {
+			return hook, errWebhookNotFound
+		}
+		return hook, err
+	}
+	return hook, nil
+}
+
+// Deliveries lists the delivery log, newest first, optionally by status.
+func Deliveries(status string, limit int) ([]models.Delivery, error) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	mdb, err := dbSession()
+	if err != nil {
+		return nil, err
+	}
+	defer mdb.Close()
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	docs := []bson.M{}
+	if err := mdb.DB(conf().DB.Name).C(tableName("delivery")).Find(filter).Sort("-created_at").Limit(limit).All(&docs); err != nil {
+		return nil, err
+	}
+	ds := []models.Delivery{}
+	for _, doc := range docs {
+		d := models.Delivery{}
+		if err := openInto(doc, deliverySealed, &d); err != nil {
+			return nil, err
+		}
+		ds = append(ds, d)
+	}
+
+	return ds, nil
+}
+
+// ReplayDeliveries puts a dead delivery back in the queue, or every dead
+// delivery when id is empty. It returns how many deliveries were requeued.
+// Deliveries that succeeded no longer have a payload and are never replayed.
+func ReplayDeliveries(id bson.ObjectId) (int, error) {
+	mdb, err := dbSession()
+	if err != nil {
+		return 0, err
+	}
+	defer mdb.Close()
+
+	filter := bson.M{"status": DeliveryDead}
+	if id != "" {
+		filter["_id"] = id
+	}
+	info, err := mdb.DB(conf().DB.Name).C(tableName("delivery")).UpdateAll(filter, bson.M{
+		"$set": bson.M{
+			"status":       DeliveryPending,
+			"attempts":     0,
+			"next_attempt": time.Now(),
+		},
+		"$unset": bson.M{"last_error": "", "last_status": ""},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if id != "" && info.Updated == 0 {
+		return 0, errDeliveryNotFound
+	}
+
+	select {
+	case webhookWake <- struct{}{}:
+	default:
+	}
+	return info.Updated, nil
+}
+
+// StartWebhookWorker runs the delivery loop in the background. It is safe to
+// call more than once.
+func StartWebhookWorker(interval time.Duration) {
+	webhookOnce.Do(func() {
+		go func() {
+			tick := time.NewTicker(interval)
+			defer tick.Stop()
+			for {
+				DispatchWebhooks()
+				select {
+				case <-tick.C:
+				case <-webhookWake:
+				}
+			}
+		}()
+	})
+}
+
+// DispatchWebhooks attempts every delivery that is due and returns how many
+// were attempted.
+func DispatchWebhooks() int {
+	attempted := 0
+	for {
+		d, err := claimDelivery()
+		if err != nil {
+			if err != mgo.ErrNotFound {
+				logger.Error(map[string]interface{}{
+					"section": "WebhookClaim",
+					"time":    time.Now(),
+				}, err.Error())
+			}
+			return attempted
+		}
+		attempted++
+		attemptDelivery(d)
+	}
+}
+
+// claimDelivery marks the next due delivery as being sent. A delivery stuck
+// in "sending" by a crashed worker becomes due again after the lease.
+func claimDelivery() (*models.Delivery, error) {
+	mdb, err := dbSession()
+	if err != nil {
+		return nil, err
+	}
+	defer mdb.Close()
+
+	col := mdb.DB(conf().DB.Name).C(tableName("delivery"))
+	if err := col.EnsureIndexKey("status", "next_attempt"); err != nil {
+		return nil, errors.New("Ensure Error: " + err.Error())
+	}
+
+	doc := bson.M{}
+	_, err = col.Find(bson.M{
+		"status":       bson.M{"$in": []string{DeliveryPending, DeliveryRetrying, DeliverySending}},
+		"next_attempt": bson.M{"$lte": time.Now()},
+	}).Sort("next_attempt").Apply(mgo.Change{
+		Update: bson.M{"$set": bson.M{
+			"status":       DeliverySending,
+			"next_attempt": time.Now().Add(2 * webhookTimeout()),
+		}},
+		ReturnNew: true,
+	}, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	d := new(models.Delivery)
+	return d, openInto(doc, deliverySealed, d)
+}
+
+func attemptDelivery(d *models.Delivery) {
+	set, unset := bson.M{}, bson.M{}
+	hook, err := webhookFor(d)
+	if err == nil {
+		set["last_status"], err = DeliverWebhook(hook, d)
+	}
+
+	d.Attempts++
+	set["attempts"] = d.Attempts
+	switch {
+	case err == nil:
+		set["status"] = DeliveryDelivered
+		set["delivered_at"] = time.Now()
+		set["last_error"] = ""
+		// The subscriber has the account data, the log keeps no copy.
+		unset["payload"] = ""
+	case d.Attempts >= webhookAttempts() || err == errWebhookNotFound:
+		set["status"] = DeliveryDead
+		set["last_error"] = err.Error()
+	default:
+		set["status"] = DeliveryRetrying
+		set["last_error"] = err.Error()
+		set["next_attempt"] = time.Now().Add(WebhookBackoff(d.Attempts))
+	}
+
+	mdb, err := dbSession()
+	if err == nil {
+		defer mdb.Close()
+		change := bson.M{"$set": set}
+		if len(unset) > 0 {
+			change["$unset"] = unset
+		}
+		err = mdb.DB(conf().DB.Name).C(tableName("delivery")).UpdateId(d.ID, change)
+	}
+	if err != nil {
+		logger.Error(map[string]interface{}{
+			"section":  "WebhookDelivery",
+			"delivery": d.ID.Hex(),
+			"time":     time.Now(),
+		}, err.Error())
+	}
+}
+
+// PurgeDeliveries removes the delivered and dead deliveries older than the
+// retention of [webhook] and returns how many were removed.
+func PurgeDeliveries() (int, error) {
+	mdb, err := dbSession()
+	if err != nil {
+		return 0, err
+	}
+	defer mdb.Close()
+
+	info, err := mdb.DB(conf().DB.Name).C(tableName("delivery")).RemoveAll(bson.M{
+		"status":     bson.M{"$in": []string{DeliveryDelivered, DeliveryDead}},
+		"created_at": bson.M{"$lt": time.Now().Add(-deliveryRetention())},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return info.Removed, nil
+}
+
+func deliveryRetention() time.Duration {
+	if conf().Webhook.Retention > 0 {
+		return conf().Webhook.Retention * 24 * time.Hour
+	}
+	return DefaultDeliveryRetention
+}
+
+func webhookAttempts() int {
+	if conf().Webhook.MaxAttempts > 0 {
+		return conf().Webhook.MaxAttempts
+	}
+	return DefaultWebhookAttempts
+}
+
+func webhookTimeout() time.Duration {
+	if conf().Webhook.Timeout > 0 {
+		return conf().Webhook.Timeout * time.Second
+	}
+	return DefaultWebhookTimeout
+}
@@ -1,36 +1,59 @@
 package main
 
 import (
+	"flag"
+	"github.com/Festum/Vibe"
+	"github.com/Festum/Vibe/controllers"
+	"github.com/Festum/Vibe/models"
 	"github.com/labstack/echo"
-	"github.com/labstack/echo/engine/standard"
-	"github.com/labstack/echo/middleware"
-	"github.com/Festum/Vibe/wrappers"
 	// "errors"
+	"log"
 	"net/http"
+	"os"
+	"time"
 )
 
 func main() {
-	e := echo.New()
-	e.Use(middleware.Logger())
-	e.Use(middleware.Recover())
+	path := flag.String("config", os.Getenv(models.EnvPrefix+"_CONFIG"), "configuration file, config.toml of the working directory or $GOPATH by default")
+	env := flag.String("env", os.Getenv(models.EnvPrefix+"_ENV"), "profile of [servers] to run with, such as production")
+	watch := flag.Bool("watch", false, "reload the log level, lists, webhook retries and phone types when the configuration file changes")
+	flag.Parse()
+
+	conf, err := models.Load(*path, *env)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := conf.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	if *watch {
+		models.Watch(func(c *models.Config, err error) {
+			if err != nil {
+				log.Println("configuration reload:", err)
+				return
+			}
+			log.Println("configuration reloaded")
+		})
+	}
+
+	// Every endpoint, documented at /openapi.json and /docs. Replace the fake
+	// gateway with the one of your payment provider.
+	e, err := vibe.New(nil, vibe.WithGateway(new(controllers.FakeGateway)))
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	e.GET("/", func(c echo.Context) error {
 		return c.String(http.StatusOK, "It's Vibe!")
 	})
-	handler := new(wrappers.Handlers)
-
-	e.GET("/auth/:id", handler.Check)
-	e.POST("/login", handler.Login)
-	e.POST("/register", handler.Register)
 
-	r := e.Group("/account")
-	r.Use(middleware.JWTWithConfig(handler.JWTCheck()))
-	r.GET("", handler.TokenResolve)
-	r.POST("/update", handler.Update)
-	r.GET("/info", handler.Get)
-	r.GET("/info/:id", handler.Get)
-	r.PUT("/:id", handler.Update)
-	r.DELETE("", handler.Delete)
+	controllers.StartWebhookWorker(time.Minute)
+	controllers.StartPurger(0)
 
-	e.Run(standard.New(":1323"))
+	server := conf.Server()
+	log.Printf("Vibe listening on %s, env %q, dc %q, tls %t", server.Addr(), conf.Env, server.DC, conf.HTTP.TLS.Cert != "")
+	if err := vibe.Run(e); err != nil {
+		log.Fatal(err)
+	}
+	log.Println("Vibe stopped")
 }
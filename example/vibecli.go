@@ -7,8 +7,14 @@ import (
 	"github.com/urfave/cli"
 	"github.com/Festum/Vibe/controllers"
 	"github.com/Festum/Vibe/models"
+	"github.com/Festum/Vibe/utils"
+	"github.com/Festum/Vibe/wrappers"
+	"gopkg.in/mgo.v2/bson"
+	"io/ioutil"
 	"os"
+	"os/user"
 	"strings"
+	"time"
 )
 
 func main() {
@@ -26,6 +32,22 @@ func main() {
 			Usage:       "force option",
 			Destination: &force,
 		},
+		cli.StringFlag{
+			Name:   "config, c",
+			Usage:  "configuration file, config.toml of the working directory or $GOPATH by default",
+			EnvVar: models.EnvPrefix + "_CONFIG",
+		},
+		cli.StringFlag{
+			Name:   "env, e",
+			Usage:  "profile of [servers] to use, such as production",
+			EnvVar: models.EnvPrefix + "_ENV",
+		},
+	}
+	app.Before = func(c *cli.Context) error {
+		if _, err := models.Load(c.GlobalString("config"), c.GlobalString("env")); err != nil {
+			return cli.NewExitError(err.Error(), 1)
+		}
+		return nil
 	}
 
 	app.Commands = []cli.Command{
@@ -36,9 +58,9 @@ func main() {
 			Subcommands: []cli.Command{
 				{
 					Name:  "show",
-					Usage: "show configurations",
+					Usage: "show configurations, secrets masked",
 					Action: func(c *cli.Context) error {
-						conf := models.Config{}.Init()
+						conf := *models.Conf().Masked()
 						if c.Args().First() != "" {
 							cmap := structs.Map(conf)
 							for k, v := range cmap {
@@ -53,6 +75,17 @@ func main() {
 						return nil
 					},
 				},
+				{
+					Name:  "check",
+					Usage: "validate the configuration, as the server does at startup",
+					Action: func(c *cli.Context) error {
+						if err := models.Conf().Validate(); err != nil {
+							return cli.NewExitError(err.Error(), 1)
+						}
+						fmt.Println("configuration is valid")
+						return nil
+					},
+				},
 				{
 					Name:  "env",
 					Usage: "show environment variables",
@@ -81,16 +114,16 @@ func main() {
 					Action: func(c *cli.Context) error {
 
 						u := new(controllers.User)
+						u.Actor = cliActor()
 						u.Email = c.Args().Get(0)
 						u.Username = c.Args().Get(1)
 						u.Password = c.Args().Get(2)
 						u.Role = c.Args().Get(3)
 
 						if err := u.Create(); err != nil {
-							switch err.Error() {
-							case "E11000":
+							if err == controllers.ErrAccountExists {
 								fmt.Println("user already exist.")
-							default:
+							} else {
 								fmt.Println(err.Error())
 							}
 						} else {
@@ -103,16 +136,28 @@ func main() {
 				},
 				{
 					Name:  "sudo",
-					Usage: "Sudo to someone",
+					Usage: "Sudo to someone.{username} --admin {admin} --reason {reason}",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "admin, a",
+							Usage: "admin account accountable for the session",
+						},
+						cli.StringFlag{
+							Name:  "reason, r",
+							Usage: "why the account is impersonated (required)",
+						},
+					},
 					Action: func(c *cli.Context) error {
 						u := new(controllers.User)
 						u.Username = c.Args().Get(0)
-						token, err := u.GenerateToken("cli", "", -1)
+						actor := models.Actor{Username: c.String("admin"), Source: "cli"}
+						token, expire, err := u.Impersonate(actor, c.String("reason"))
 						if err != nil {
 							fmt.Println(err)
 							return nil
 						}
 						fmt.Println(token)
+						fmt.Println("expires at " + expire.Format(time.RFC3339))
 
 						return nil
 					},
@@ -133,10 +178,11 @@ func main() {
 				},
 				{
 					Name:  "del",
-					Usage: "delete an existing user by username",
+					Usage: "delete an existing user by username, restorable until purged",
 					Action: func(c *cli.Context) error {
 						u := new(controllers.User)
 						u.Username = c.Args().Get(0)
+						u.Actor = cliActor()
 						if err := u.Delete(); err != nil {
 							fmt.Println(err)
 							return nil
@@ -145,6 +191,113 @@ func main() {
 						return nil
 					},
 				},
+				{
+					Name:  "restore",
+					Usage: "restore a deleted user by username",
+					Action: func(c *cli.Context) error {
+						u := controllers.User{Username: c.Args().Get(0), Actor: cliActor()}
+						if err := u.Restore(); err != nil {
+							fmt.Println(err)
+							return nil
+						}
+						fmt.Println("user " + c.Args().First() + " restored")
+						return nil
+					},
+				},
+				{
+					Name:  "purge",
+					Usage: "remove deleted users for good, all expired ones or {username}",
+					Action: func(c *cli.Context) error {
+						if c.NArg() > 0 {
+							u := controllers.User{Username: c.Args().Get(0), Actor: cliActor()}
+							if err := u.Purge(); err != nil {
+								fmt.Println(err)
+								return nil
+							}
+							fmt.Println("user " + c.Args().First() + " purged")
+							return nil
+						}
+						n, err := controllers.PurgeDeleted()
+						if err != nil {
+							fmt.Println(err)
+							return nil
+						}
+						fmt.Printf("%d users purged\n", n)
+						return nil
+					},
+				},
+				{
+					Name:  "export",
+					Usage: "export everything stored about a user as a zip archive.{username} --out {file}",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "out, o",
+							Usage: "archive path, defaults to {username}-export.zip",
+						},
+					},
+					Action: func(c *cli.Context) error {
+						u := controllers.User{Username: c.Args().Get(0), Actor: cliActor()}
+						out := c.String("out")
+						if out == "" {
+							out = u.Username + "-export.zip"
+						}
+						f, err := os.OpenFile(out, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+						if err != nil {
+							fmt.Println(err)
+							return nil
+						}
+						defer f.Close()
+						if err := u.ExportData(f); err != nil {
+							os.Remove(out)
+							fmt.Println(err)
+							return nil
+						}
+						fmt.Println("user " + u.Username + " exported to " + out)
+						return nil
+					},
+				},
+				{
+					Name:  "anonymize",
+					Usage: "erase personal data of {username}, or of every user inactive for --inactive years",
+					Flags: []cli.Flag{
+						cli.IntFlag{
+							Name:  "inactive",
+							Usage: "years without login",
+						},
+					},
+					Action: func(c *cli.Context) error {
+						if years := c.Int("inactive"); years > 0 {
+							n, err := controllers.AnonymizeInactive(years)
+							if err != nil {
+								fmt.Println(err)
+							}
+							fmt.Printf("%d users anonymized\n", n)
+							return nil
+						}
+						u := controllers.User{Username: c.Args().Get(0), Actor: cliActor()}
+						if err := u.Anonymize(); err != nil {
+							fmt.Println(err)
+							return nil
+						}
+						fmt.Println("user " + c.Args().First() + " anonymized as " + u.Username)
+						return nil
+					},
+				},
+				{
+					Name:  "phones",
+					Usage: "store every phone number in E.164, needed before enabling unique phones",
+					Action: func(c *cli.Context) error {
+						n, failed, err := controllers.NormalizePhones(nil)
+						if err != nil {
+							fmt.Println(err)
+						}
+						fmt.Printf("%d phone numbers normalized\n", n)
+						for _, username := range failed {
+							fmt.Println("invalid or duplicate phone: " + username)
+						}
+						return nil
+					},
+				},
 				{
 					Name:  "update",
 					Usage: "update an existing user",
@@ -164,6 +317,7 @@ func main() {
 						}
 						ogRole := u.Role
 						u.Role = c.Args().Get(1)
+						u.Actor = cliActor()
 						if err := u.Update(); err != nil {
 							fmt.Printf("Unale to update user " + c.Args().Get(0) + " with role " + c.Args().Get(1) + ". " + err.Error())
 							return nil
@@ -181,7 +335,12 @@ func main() {
 					Name:  "enable",
 					Usage: "enable user",
 					Action: func(c *cli.Context) error {
-						u := controllers.User{Username: c.Args().Get(0), IsDisabled: false}
+						u := controllers.User{Username: c.Args().Get(0)}
+						if err := u.Get(); err != nil {
+							fmt.Printf("Invalid username " + c.Args().Get(0) + ". " + err.Error())
+							return nil
+						}
+						u.IsDisabled, u.Actor = false, cliActor()
 						if err := u.Update(); err != nil {
 							fmt.Printf("Unale to update user " + c.Args().Get(0) + ". " + err.Error())
 							return nil
@@ -195,7 +354,12 @@ func main() {
 					Name:  "disable",
 					Usage: "disable user",
 					Action: func(c *cli.Context) error {
-						u := controllers.User{Username: c.Args().Get(0), IsDisabled: true}
+						u := controllers.User{Username: c.Args().Get(0)}
+						if err := u.Get(); err != nil {
+							fmt.Printf("Invalid username " + c.Args().Get(0) + ". " + err.Error())
+							return nil
+						}
+						u.IsDisabled, u.Actor = true, cliActor()
 						if err := u.Update(); err != nil {
 							fmt.Printf("Unale to update user " + c.Args().Get(0) + ". " + err.Error())
 							return nil
@@ -207,7 +371,219 @@ func main() {
 				},
 			},
 		},
+		{
+			Name:  "keys",
+			Usage: "Field encryption keys",
+			Subcommands: []cli.Command{
+				{
+					Name:  "generate",
+					Usage: "print a random key for the [crypto] section",
+					Action: func(c *cli.Context) error {
+						key, err := new(utils.Keyring).GenerateKey()
+						if err != nil {
+							fmt.Println(err)
+							return nil
+						}
+						fmt.Println(key)
+						return nil
+					},
+				},
+				{
+					Name:  "rotate",
+					Usage: "re-encrypt stored fields with the primary key, the service can keep running",
+					Action: func(c *cli.Context) error {
+						n, err := controllers.RotateKeys(func(done int) {
+							if done%1000 == 0 {
+								fmt.Printf("%d records re-encrypted\n", done)
+							}
+						})
+						if err != nil {
+							fmt.Println(err)
+						}
+						fmt.Printf("%d records re-encrypted\n", n)
+						return nil
+					},
+				},
+			},
+		},
+		{
+			Name:  "audit",
+			Usage: "Audit trail operations",
+			Subcommands: []cli.Command{
+				{
+					Name:  "list",
+					Usage: "list audit entries in JSON",
+					Flags: []cli.Flag{
+						cli.StringFlag{Name: "actor", Usage: "filter by actor username"},
+						cli.StringFlag{Name: "target", Usage: "filter by target username"},
+						cli.StringFlag{Name: "action", Usage: "filter by action"},
+						cli.Int64Flag{Name: "after", Usage: "only entries after this sequence number"},
+						cli.IntFlag{Name: "limit", Value: controllers.DefaultPageSize, Usage: "maximum number of entries"},
+					},
+					Action: func(c *cli.Context) error {
+						q := &controllers.AuditQuery{
+							Actor:    c.String("actor"),
+							Target:   c.String("target"),
+							Action:   c.String("action"),
+							AfterSeq: c.Int64("after"),
+							Limit:    c.Int("limit"),
+						}
+						entries, err := q.Find()
+						if err != nil {
+							fmt.Println(err)
+							return nil
+						}
+						for _, e := range entries {
+							ej, _ := json.Marshal(e)
+							fmt.Println(string(ej))
+						}
+						return nil
+					},
+				},
+				{
+					Name:  "verify",
+					Usage: "verify the hash chain of the audit trail",
+					Action: func(c *cli.Context) error {
+						checked, err := controllers.VerifyAudit()
+						if err != nil {
+							fmt.Println(err)
+							return cli.NewExitError("", 1)
+						}
+						fmt.Printf("audit trail intact, %d entries checked\n", checked)
+						return nil
+					},
+				},
+			},
+		},
+		{
+			Name:    "webhook",
+			Aliases: []string{"w"},
+			Usage:   "Webhook operations",
+			Subcommands: []cli.Command{
+				{
+					Name:  "list",
+					Usage: "list deliveries in JSON",
+					Flags: []cli.Flag{
+						cli.StringFlag{Name: "status", Usage: "pending, retrying, delivered or dead"},
+						cli.IntFlag{Name: "limit", Value: controllers.DefaultPageSize, Usage: "maximum number of deliveries"},
+					},
+					Action: func(c *cli.Context) error {
+						ds, err := controllers.Deliveries(c.String("status"), c.Int("limit"))
+						if err != nil {
+							fmt.Println(err)
+							return nil
+						}
+						for _, d := range ds {
+							dj, _ := json.Marshal(d)
+							fmt.Println(string(dj))
+						}
+						return nil
+					},
+				},
+				{
+					Name:  "replay",
+					Usage: "replay a failed delivery.{delivery id}, every dead delivery when omitted",
+					Action: func(c *cli.Context) error {
+						var id bson.ObjectId
+						if c.Args().First() != "" {
+							if !bson.IsObjectIdHex(c.Args().First()) {
+								fmt.Println("Invalid delivery id " + c.Args().First())
+								return nil
+							}
+							id = bson.ObjectIdHex(c.Args().First())
+						}
+						n, err := controllers.ReplayDeliveries(id)
+						if err != nil {
+							fmt.Println(err)
+							return nil
+						}
+						fmt.Printf("%d deliveries requeued, %d attempted\n", n, controllers.DispatchWebhooks())
+						return nil
+					},
+				},
+			},
+		},
+		{
+			Name:  "openapi",
+			Usage: "write the OpenAPI document of the server.--out {file}",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "out, o",
+					Usage: "document path, printed when omitted",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				doc, err := json.MarshalIndent(new(wrappers.Handlers).Spec(), "", "  ")
+				if err != nil {
+					return err
+				}
+				doc = append(doc, '\n')
+				if c.String("out") == "" {
+					os.Stdout.Write(doc)
+					return nil
+				}
+				if err := ioutil.WriteFile(c.String("out"), doc, 0644); err != nil {
+					return cli.NewExitError(err.Error(), 1)
+				}
+				fmt.Println("OpenAPI document written to " + c.String("out"))
+				return nil
+			},
+		},
+		{
+			Name:  "doctor",
+			Usage: "run the readiness checks of /readyz against the local configuration",
+			Action: func(c *cli.Context) error {
+				r := controllers.Ready()
+				for _, check := range r.Checks {
+					fmt.Printf("%-14s %-6s %9.1fms %s\n", check.Name, check.Status, check.Latency, check.Error)
+				}
+				if r.Status != controllers.CheckOK {
+					return cli.NewExitError("not ready", 1)
+				}
+				fmt.Println("ready")
+				return nil
+			},
+		},
+		{
+			Name:  "i18n",
+			Usage: "Translations of user-facing texts",
+			Subcommands: []cli.Command{
+				{
+					Name:  "missing",
+					Usage: "list the texts without translation.{language}, every language when omitted",
+					Action: func(c *cli.Context) error {
+						langs := controllers.Languages()[1:]
+						if c.Args().First() != "" {
+							langs = []string{c.Args().First()}
+						}
+						incomplete := false
+						for _, lang := range langs {
+							missing := controllers.MissingMessages(lang)
+							if len(missing) > 0 {
+								incomplete = true
+							}
+							for _, key := range missing {
+								fmt.Println(lang + "\t" + key)
+							}
+						}
+						if incomplete {
+							return cli.NewExitError("", 1)
+						}
+						return nil
+					},
+				},
+			},
+		},
 	}
 
 	app.Run(os.Args)
 }
+
+// cliActor names the operator running vibecli in the audit trail.
+func cliActor() *models.Actor {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	return &models.Actor{Username: name, Source: "cli"}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"gopkg.in/mgo.v2/bson"
+	"time"
+)
+
+// Actor identifies who performed an audited action.
+type Actor struct {
+	Username       string `json:"username" bson:"username"`
+	Role           string `json:"role,omitempty" bson:"role,omitempty"`
+	Source         string `json:"source" bson:"source"` //jwt,cli,self,system
+	ImpersonatedBy string `json:"impersonated_by,omitempty" bson:"impersonated_by,omitempty"`
+	IP             string `json:"-" bson:"-"`
+}
+
+// AuditEntry is one append-only record of the audit collection. Entries are
+// chained by Seq and PrevHash so that a removed or edited entry breaks Hash
+// of every entry after it.
+type AuditEntry struct {
+	ID       bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	Seq      int64         `json:"seq"`
+	Action   string        `json:"action"`
+	Actor    Actor         `json:"actor"`
+	Target   string        `json:"target"`
+	Changes  []FieldChange `json:"changes,omitempty" bson:"changes,omitempty"`
+	Reason   string        `json:"reason,omitempty" bson:"reason,omitempty"`
+	IP       string        `json:"ip,omitempty" bson:"ip,omitempty"`
+	When     time.Time     `json:"when"`
+	PrevHash string        `json:"prev_hash" bson:"prev_hash"`
+	Hash     string        `json:"hash"`
+}
+
+// FieldChange is the before and after value of one changed document field.
+// Values of personal and secret fields are stored redacted.
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
@@ -1,8 +1,22 @@
 package models
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -11,11 +25,23 @@ type Config struct {
 	Build   string
 	Owner   ownerInfo
 	DB      database `mapstructure:"database"`
-	Servers server
+	Servers map[string]server
+	HTTP    httpServer `mapstructure:"http"`
 	Logger  logger
 	JWT     jwt
 	List    list
 	Social  map[string]social
+	Webhook webhook
+	Account account
+	Export  export
+	Crypto  crypto
+	Avatar  avatar
+	Phone   phone
+	I18n    i18n
+
+	Validation map[string]map[string]string //field rules by operation, such as create
+
+	Env string `mapstructure:"-"` //name of the servers profile in use
 }
 
 type ownerInfo struct {
@@ -26,20 +52,69 @@ type ownerInfo struct {
 }
 
 type database struct {
-	Host    string
-	Ports   []int
-	Name    string
-	ConnMax int `mapstructure:"connection_max"`
-	Enabled bool
-	Table   map[string]string
+	Host     string
+	Ports    []int
+	Name     string
+	User     string
+	Password string `secret:"true"`
+	ConnMax  int    `mapstructure:"connection_max"`
+	Enabled  bool
+	Table    map[string]string
 }
 
+// server is an environment profile. Its tables, such as [servers.beta.jwt],
+// override the sections of the same name.
 type server struct {
 	IP   string
 	DC   string
 	Port string
 }
 
+// DefaultPort is listened on without a profile or a port in it.
+const DefaultPort = "1323"
+
+// Addr is the address the server binds to.
+func (s server) Addr() string {
+	port := s.Port
+	if port == "" {
+		port = DefaultPort
+	}
+	return net.JoinHostPort(s.IP, port)
+}
+
+type httpServer struct {
+	ShutdownTimeout time.Duration //seconds in-flight requests are drained for on SIGTERM
+	TrustedProxies  []string      `mapstructure:"trusted_proxies"` //addresses or CIDRs whose X-Real-IP and X-Forwarded-For are honoured
+	PublicURL       string        `mapstructure:"public_url"`      //absolute URL clients reach the server at, starts the links sent by email
+	TLS             tlsConfig
+}
+
+// TrustsProxy tells if ip is one of the trusted proxies.
+func (h httpServer) TrustsProxy(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, p := range h.TrustedProxies {
+		if _, n, err := net.ParseCIDR(p); err == nil {
+			if n.Contains(addr) {
+				return true
+			}
+		} else if addr.Equal(net.ParseIP(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+type tlsConfig struct {
+	Cert       string            //PEM certificate chain file, TLS is served when set, reloaded when renewed
+	Key        string            //PEM private key file of Cert
+	ClientCA   string            `mapstructure:"client_ca"`   //PEM file of the CAs of client certificates, enables mutual TLS
+	ClientAuth string            `mapstructure:"client_auth"` //optional or required client certificates
+	Clients    map[string]string //usernames by certificate common name, the name itself when absent
+}
+
 type logger struct {
 	Level string
 	Debug string
@@ -51,10 +126,11 @@ type logger struct {
 }
 
 type jwt struct {
-	SigningKey    string
-	SigningMethod string
-	Bearer        string
-	TokenTTL      time.Duration
+	SigningKey       string `secret:"true"`
+	SigningMethod    string
+	Bearer           string
+	TokenTTL         time.Duration //hours
+	ImpersonationTTL time.Duration //minutes
 }
 
 type list struct {
@@ -62,23 +138,489 @@ type list struct {
 	Black []string
 }
 
+type webhook struct {
+	MaxAttempts int
+	Backoff     time.Duration //seconds, doubled after every failed attempt
+	Timeout     time.Duration //seconds
+	Retention   time.Duration //days delivered and dead deliveries are kept
+	Endpoints   []Webhook
+}
+
+type account struct {
+	DeleteGrace   time.Duration //days a deleted account can be restored
+	PurgeInterval time.Duration //minutes between purges of expired accounts
+	InactiveYears int           //anonymize accounts without login for this many years, 0 disables
+}
+
+type export struct {
+	Dir     string        //where archives are written, defaults to the temp dir
+	LinkTTL time.Duration //hours a download link stays valid
+}
+
+type crypto struct {
+	Primary  string            //id of the key sealing new values
+	Keys     map[string]string `secret:"true"` //base64 encoded 32 byte keys by id
+	IndexKey string            `secret:"true"` //base64 encoded HMAC key of blind indexes
+}
+
+type avatar struct {
+	Store   string //local or gridfs
+	Dir     string //directory of the local store
+	BaseURL string //URL avatars are served under, /avatars of the mount path by default
+	MaxSize int64  //kilobytes
+	Sizes   []int  //pixel sizes of the square variants
+}
+
+type phone struct {
+	Unique bool     //refuse a number already used by another account
+	Types  []string //accepted number types such as mobile, any when empty
+}
+
+type i18n struct {
+	Dir     string //directory of the <language>.json locale files
+	Default string //language when the caller has no supported preference
+}
+
 type social struct {
-	Key    string
-	Secret string
+	Key    string `secret:"true"`
+	Secret string `secret:"true"`
 }
 
-func (c Config) Init() Config {
-	var vp = viper.New()
+// EnvPrefix starts the environment variables overriding configuration keys,
+// such as VIBE_JWT_SIGNINGKEY for SigningKey of [jwt]. VIBE_ENV names the
+// profile used when none is given.
+const EnvPrefix = "VIBE"
+
+var (
+	confMu   sync.RWMutex
+	current  *Config
+	vp       *viper.Viper
+	profile  string
+	watchers []func(*Config, error)
+
+	signingMethods = []string{"HS256", "HS384", "HS512"}
+	logLevels      = []string{"", "debug", "info", "warn", "error"}
+	clientAuths    = []string{"", "optional", "required"}
+)
 
-	vp.SetConfigName("config")
-	vp.AddConfigPath(".")
-	vp.AddConfigPath("$GOPATH/")
-	vp.SetConfigType("toml")
-	err := vp.ReadInConfig()
+// ConfigError lists every problem of a configuration.
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+func (e *ConfigError) add(format string, args ...interface{}) {
+	e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+}
+
+// Load reads the configuration at path, or config.toml in the working
+// directory or $GOPATH when path is empty, applies the overrides of the env
+// profile of [servers] then the VIBE_ environment variables, and makes it the
+// configuration returned by Conf. A missing default file leaves the defaults
+// and the environment.
+func Load(path, env string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigType("toml")
+	if path != "" {
+		v.SetConfigFile(path)
+	} else {
+		v.SetConfigName("config")
+		v.AddConfigPath(".")
+		v.AddConfigPath("$GOPATH/")
+	}
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok || path != "" {
+			return nil, fmt.Errorf("reading configuration: %s", err)
+		}
+	}
+
+	c, err := decode(v, env)
 	if err != nil {
-		fmt.Errorf("Fatal error config file: %s \n", err)
+		return nil, err
+	}
+
+	confMu.Lock()
+	current, vp, profile = c, v, env
+	confMu.Unlock()
+	return c, nil
+}
+
+// Conf is the loaded configuration, the default one is loaded on first use
+// when Load was not called. It is replaced as a whole on reload and must not
+// be changed.
+func Conf() *Config {
+	confMu.RLock()
+	c := current
+	confMu.RUnlock()
+	if c != nil {
+		return c
 	}
-	vp.Unmarshal(&c)
 
+	c, err := Load("", os.Getenv(EnvPrefix+"_ENV"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c = new(Config)
+		confMu.Lock()
+		if current == nil {
+			current = c
+		}
+		confMu.Unlock()
+	}
 	return c
 }
+
+// Use makes c the configuration returned by Conf, for applications building
+// it themselves. Its secrets are taken as they are and Watch has no file to
+// follow afterwards.
+func Use(c *Config) {
+	confMu.Lock()
+	current, vp, profile = c, nil, c.Env
+	confMu.Unlock()
+}
+
+// Init returns the loaded configuration.
+//
+// Deprecated: use Load at startup and Conf afterwards.
+func (c Config) Init() Config {
+	return *Conf()
+}
+
+func decode(v *viper.Viper, env string) (*Config, error) {
+	// Overrides are set on a copy of the file settings: set on v, they would
+	// outlive the reloads and shadow the values changed in the file.
+	o := viper.New()
+	if err := o.MergeConfigMap(v.AllSettings()); err != nil {
+		return nil, fmt.Errorf("decoding configuration: %s", err)
+	}
+	if err := applyProfile(o, env); err != nil {
+		return nil, err
+	}
+	errs := new(ConfigError)
+	applyEnv(o, reflect.TypeOf(Config{}), "", errs)
+	if len(errs.Problems) > 0 {
+		return nil, errs
+	}
+
+	c := &Config{Env: env}
+	if err := o.Unmarshal(c); err != nil {
+		return nil, fmt.Errorf("decoding configuration: %s", err)
+	}
+	walkSecrets(reflect.ValueOf(c).Elem(), false, "", resolveSecret, errs)
+	if len(errs.Problems) > 0 {
+		return nil, errs
+	}
+	return c, nil
+}
+
+// applyProfile sets the keys of the tables of the env profile over their
+// sections.
+func applyProfile(v *viper.Viper, env string) error {
+	if env == "" {
+		return nil
+	}
+	key := "servers." + strings.ToLower(env)
+	if !v.IsSet(key) {
+		names := []string{}
+		for name := range v.GetStringMap("servers") {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown environment profile %q, configured: %s", env, strings.Join(names, ", "))
+	}
+
+	for section, value := range v.GetStringMap(key) {
+		if table, ok := value.(map[string]interface{}); ok {
+			setLeaves(v, section, table)
+		}
+	}
+	return nil
+}
+
+func setLeaves(v *viper.Viper, prefix string, table map[string]interface{}) {
+	for k, value := range table {
+		if sub, ok := value.(map[string]interface{}); ok {
+			setLeaves(v, prefix+"."+k, sub)
+			continue
+		}
+		v.Set(prefix+"."+k, value)
+	}
+}
+
+// Server is the profile in use, empty without one.
+func (c *Config) Server() server {
+	return c.Servers[strings.ToLower(c.Env)]
+}
+
+// applyEnv sets every key of t found in the environment. Durations are
+// numbers in the unit of their key, lists are comma separated. Maps and lists
+// of tables are left to the file.
+func applyEnv(v *viper.Viper, t reflect.Type, prefix string, errs *ConfigError) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := f.Tag.Get("mapstructure")
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = strings.ToLower(f.Name)
+		}
+		key = prefix + key
+
+		if f.Type.Kind() == reflect.Struct && f.Type != reflect.TypeOf(time.Time{}) {
+			applyEnv(v, f.Type, key+".", errs)
+			continue
+		}
+		env := EnvPrefix + "_" + strings.ToUpper(strings.Replace(key, ".", "_", -1))
+		raw, ok := os.LookupEnv(env)
+		if !ok {
+			continue
+		}
+
+		var value interface{}
+		var err error
+		switch f.Type.Kind() {
+		case reflect.String:
+			value = raw
+		case reflect.Bool:
+			value, err = strconv.ParseBool(raw)
+		case reflect.Int, reflect.Int64:
+			value, err = strconv.ParseInt(raw, 10, 64)
+		case reflect.Slice:
+			items := []string{}
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			if f.Type.Elem().Kind() == reflect.String {
+				value = items
+				break
+			}
+			if f.Type.Elem().Kind() != reflect.Int {
+				continue
+			}
+			ints := []int{}
+			for _, item := range items {
+				n, perr := strconv.Atoi(item)
+				if perr != nil {
+					err = perr
+				}
+				ints = append(ints, n)
+			}
+			value = ints
+		default:
+			if f.Type == reflect.TypeOf(time.Time{}) {
+				value, err = time.Parse(time.RFC3339, raw)
+				break
+			}
+			continue
+		}
+		if err != nil {
+			errs.add("%s: %s", env, err)
+			continue
+		}
+		v.Set(key, value)
+	}
+}
+
+// Validate reports every setting the server cannot start with.
+func (c *Config) Validate() error {
+	errs := new(ConfigError)
+
+	if c.JWT.SigningKey == "" {
+		errs.add("jwt.signingkey is required")
+	}
+	if !contains(signingMethods, c.JWT.SigningMethod) {
+		errs.add("jwt.signingmethod %q is not one of %s", c.JWT.SigningMethod, strings.Join(signingMethods, ", "))
+	}
+	for key, ttl := range map[string]time.Duration{
+		"jwt.tokenttl":          c.JWT.TokenTTL,
+		"jwt.impersonationttl":  c.JWT.ImpersonationTTL,
+		"export.linkttl":        c.Export.LinkTTL,
+		"account.deletegrace":   c.Account.DeleteGrace,
+		"account.purgeinterval": c.Account.PurgeInterval,
+		"webhook.backoff":       c.Webhook.Backoff,
+		"webhook.timeout":       c.Webhook.Timeout,
+		"webhook.retention":     c.Webhook.Retention,
+		"http.shutdowntimeout":  c.HTTP.ShutdownTimeout,
+	} {
+		if ttl < 0 {
+			errs.add("%s must not be negative", key)
+		}
+	}
+
+	if port := c.Server().Port; port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			errs.add("servers.%s.port %q is not a port number", c.Env, port)
+		}
+	}
+
+	for _, p := range c.HTTP.TrustedProxies {
+		if _, _, err := net.ParseCIDR(p); err != nil && net.ParseIP(p) == nil {
+			errs.add("http.trusted_proxies %q is not an address or CIDR", p)
+		}
+	}
+	if c.HTTP.PublicURL != "" {
+		u, err := url.Parse(c.HTTP.PublicURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+			errs.add("http.public_url %q is not an absolute http or https URL", c.HTTP.PublicURL)
+		}
+	}
+	c.HTTP.TLS.validate(errs)
+
+	if !contains(logLevels, strings.ToLower(c.Logger.Level)) {
+		errs.add("logger.level %q is not one of debug, info, warn or error", c.Logger.Level)
+	}
+	for key, path := range map[string]string{
+		"logger.debug": c.Logger.Debug,
+		"logger.info":  c.Logger.Info,
+		"logger.error": c.Logger.Error,
+		"logger.warn":  c.Logger.Warn,
+		"logger.fatal": c.Logger.Fatal,
+		"logger.panic": c.Logger.Panic,
+	} {
+		if path == "" {
+			continue
+		}
+		if err := writable(path); err != nil {
+			errs.add("%s: %s", key, err)
+		}
+	}
+
+	if len(errs.Problems) == 0 {
+		return nil
+	}
+	sort.Strings(errs.Problems)
+	return errs
+}
+
+func (t tlsConfig) validate(errs *ConfigError) {
+	if (t.Cert == "") != (t.Key == "") {
+		errs.add("http.tls.cert and http.tls.key are set together")
+	} else if t.Cert != "" {
+		if _, err := tls.LoadX509KeyPair(t.Cert, t.Key); err != nil {
+			errs.add("http.tls: %s", err)
+		}
+	}
+
+	if t.ClientCA != "" {
+		if t.Cert == "" {
+			errs.add("http.tls.client_ca needs http.tls.cert")
+		}
+		if _, err := t.ClientCAs(); err != nil {
+			errs.add("http.tls.client_ca: %s", err)
+		}
+	}
+	if !contains(clientAuths, t.ClientAuth) {
+		errs.add("http.tls.client_auth %q is not one of optional or required", t.ClientAuth)
+	}
+}
+
+// ClientCAs is the pool of the CAs client certificates are verified with.
+func (t tlsConfig) ClientCAs() (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(t.ClientCA)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no PEM certificate found")
+	}
+	return pool, nil
+}
+
+// writable tells if the log file at path can be appended to. Nothing is
+// created, missing files and directories are left to the logger: the closest
+// existing directory above them must be a directory.
+func writable(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err == nil {
+		return f.Close()
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			return nil
+		}
+		if !os.IsNotExist(err) || dir == filepath.Dir(dir) {
+			return err
+		}
+	}
+}
+
+// Watch reloads the configuration file whenever it changes. Only the safe
+// settings, the log level, the lists, the webhook retries and the phone types,
+// are taken over while running; others wait for a restart. onReload is called
+// after every attempt with the configuration in use and the reason a change
+// was refused or only partly applied.
+func Watch(onReload func(*Config, error)) {
+	confMu.Lock()
+	v, env := vp, profile
+	first := len(watchers) == 0
+	if onReload != nil {
+		watchers = append(watchers, onReload)
+	}
+	confMu.Unlock()
+	if v == nil || !first {
+		return
+	}
+
+	v.OnConfigChange(func(fsnotify.Event) {
+		c, err := reload(v, env)
+		confMu.RLock()
+		ws := watchers
+		confMu.RUnlock()
+		for _, w := range ws {
+			w(c, err)
+		}
+	})
+	v.WatchConfig()
+}
+
+func reload(v *viper.Viper, env string) (*Config, error) {
+	old := Conf()
+	next, err := decode(v, env)
+	if err != nil {
+		return old, err
+	}
+	if err := next.Validate(); err != nil {
+		return old, err
+	}
+
+	c := *old
+	c.Logger.Level = next.Logger.Level
+	c.List = next.List
+	c.Webhook.MaxAttempts = next.Webhook.MaxAttempts
+	c.Webhook.Backoff = next.Webhook.Backoff
+	c.Webhook.Timeout = next.Webhook.Timeout
+	c.Webhook.Retention = next.Webhook.Retention
+	c.Phone.Types = next.Phone.Types
+
+	confMu.Lock()
+	current = &c
+	confMu.Unlock()
+
+	if !reflect.DeepEqual(&c, next) {
+		return &c, errors.New("settings other than the log level, lists, webhook retries and phone types apply after a restart")
+	}
+	return &c, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"gopkg.in/mgo.v2/bson"
+	"time"
+)
+
+// Export is a personal data export requested by a user. The archive is built
+// in the background and can be downloaded until ExpiresAt.
+type Export struct {
+	ID        bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	Username  string        `json:"username"`
+	Status    string        `json:"status"` //pending,running,ready,failed,expired
+	Error     string        `json:"error,omitempty" bson:"error,omitempty"`
+	Size      int64         `json:"size,omitempty" bson:"size,omitempty"`
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+	ReadyAt   time.Time     `json:"ready_at,omitempty" bson:"ready_at,omitempty"`
+	ExpiresAt time.Time     `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	URL       string        `json:"download_url,omitempty" bson:"-"` //signed link, only set while ready
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretProvider resolves the secret references of one scheme. A setting
+// tagged secret holding "scheme:ref" is replaced by Resolve(ref) when the
+// configuration is loaded or reloaded, other values are taken literally.
+type SecretProvider interface {
+	Resolve(ref string) (string, error)
+}
+
+// FileSecrets reads file:/path references, such as Docker or Kubernetes
+// secrets. The trailing newline is dropped.
+type FileSecrets struct{}
+
+func (FileSecrets) Resolve(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// EnvSecrets reads env:NAME references from the environment.
+type EnvSecrets struct{}
+
+func (EnvSecrets) Resolve(name string) (string, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return v, nil
+}
+
+// Masked replaces every secret value for display.
+const Masked = "******"
+
+var (
+	providersMu     sync.RWMutex
+	secretProviders = map[string]SecretProvider{
+		"file": FileSecrets{},
+		"env":  EnvSecrets{},
+	}
+)
+
+// RegisterSecretProvider resolves the references of scheme with p from the
+// next load on, such as a vault client for vault:path references.
+func RegisterSecretProvider(scheme string, p SecretProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	secretProviders[scheme] = p
+}
+
+func resolveSecret(value string) (string, error) {
+	i := strings.Index(value, ":")
+	if i < 0 {
+		return value, nil
+	}
+	providersMu.RLock()
+	p, ok := secretProviders[value[:i]]
+	providersMu.RUnlock()
+	if !ok {
+		return value, nil
+	}
+	return p.Resolve(value[i+1:])
+}
+
+// Masked returns a copy of c with its secrets hidden.
+func (c *Config) Masked() *Config {
+	masked := *c
+	walkSecrets(reflect.ValueOf(&masked).Elem(), false, "", func(s string) (string, error) {
+		if s == "" {
+			return "", nil
+		}
+		return Masked, nil
+	}, new(ConfigError))
+	return &masked
+}
+
+// walkSecrets replaces the secret strings of v, a settable value, by fn of
+// them. Maps and slices on the way are copied so v shares nothing with the
+// value it was copied from.
+func walkSecrets(v reflect.Value, secret bool, path string, fn func(string) (string, error), errs *ConfigError) {
+	switch v.Kind() {
+	case reflect.String:
+		if !secret {
+			return
+		}
+		s, err := fn(v.String())
+		if err != nil {
+			errs.add("%s: %s", path, err)
+			return
+		}
+		v.SetString(s)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			key := f.Tag.Get("mapstructure")
+			if key == "" {
+				key = strings.ToLower(f.Name)
+			}
+			walkSecrets(v.Field(i), secret || f.Tag.Get("secret") == "true", joinKey(path, key), fn, errs)
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		m := reflect.MakeMap(v.Type())
+		for _, k := range v.MapKeys() {
+			e := reflect.New(v.Type().Elem()).Elem()
+			e.Set(v.MapIndex(k))
+			walkSecrets(e, secret, joinKey(path, fmt.Sprint(k.Interface())), fn, errs)
+			m.SetMapIndex(k, e)
+		}
+		v.Set(m)
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(s, v)
+		for i := 0; i < s.Len(); i++ {
+			walkSecrets(s.Index(i), secret, fmt.Sprintf("%s[%d]", path, i), fn, errs)
+		}
+		v.Set(s)
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -2,38 +2,41 @@ package models
 
 import (
 	"github.com/markbates/goth"
-	"github.com/mholt/binding"
 	"gopkg.in/mgo.v2/bson"
-	"net/http"
 	"time"
 )
 
 // User contains details about the user of a page.
 type User struct {
-	ID                bson.ObjectId `json:"-" bson:"_id,omitempty"` //ObjectId().getTimestamp() can get created date
-	Email             string        `json:"email" valid:email,required`
-	Username          string        `json:"username" valid:"alphanum,required"`
-	Password          string        `json:"password,omitempty" bson:"-" valid:"alphanum,required"`
+	ID                bson.ObjectId `json:"id,omitempty" bson:"_id,omitempty"` //ObjectId().getTimestamp() can get created date
+	Email             string        `json:"email"`
+	Username          string        `json:"username"`
+	Password          string        `json:"password,omitempty" bson:"-"`
 	EncryptedPassword string        `json:"-" bson:"encrypted_password"`
 	Salt              string        `json:"-" bson:"salt"`
-	Role              string        `json:"role" valid:"role,required"` //sysadmin,admin,member,vip,banned
+	Role              string        `json:"role"` //sysadmin,admin,member,vip,banned
 	DisplayName       string        `json:"display_name" bson:"display_name"`
-	GivenName         string        `json:"given_name" bson:"given_name"`
-	FamilyName        string        `json:"family_name" bson:"family_name"`
+	GivenName         string        `json:"given_name" bson:"given_name" encrypt:"true"`
+	FamilyName        string        `json:"family_name" bson:"family_name" encrypt:"true"`
 	Language          string        `json:"language"`
 	Avatar            string        `json:"avatar"`
 	ShortBio          string        `json:"short_bio" bson:"short_bio"`
 	LongBio           string        `json:"long_bio" bson:"long_bio"`
 	Country           string        `json:"country"`
-	Phone             string        `json:"phone"`
-	Birth             time.Time     `json:"birth"`
+	Phone             string        `json:"phone" encrypt:"index"`
+	Birth             time.Time     `json:"birth" encrypt:"true"`
 	Age               int64         `json:"age"`
 	Gender            int64         `json:"gender"` //0=undefined;1=male;2=female;3=shemale
 	Social            UserSocial    `json:"social"`
-	IsDisabled        bool          `json:"is_disabled" bson:"is_disabled" valid:"boolean, required"`
-	CreatedAt         time.Time     `json:"created_at" bson:"created_at" valid:"required"`
-	UpdatedAt         time.Time     `json:"updated_at" bson:"updated_at" valid:"required"`
-	LastLogin         time.Time     `json:"last_login" bson:"last_login" valid:"required"`
+	IsDisabled        bool          `json:"is_disabled" bson:"is_disabled"`
+	CreatedAt         time.Time     `json:"created_at" bson:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at" bson:"updated_at"`
+	LastLogin         time.Time     `json:"last_login" bson:"last_login"`
+	Version           int64         `json:"version"`                                                //incremented on every update, served as ETag
+	DeletedAt         *time.Time    `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`       //set while the account is in the trash
+	PurgeAt           *time.Time    `json:"purge_at,omitempty" bson:"purge_at,omitempty"`           //when a deleted account is removed for good
+	AnonymizedAt      *time.Time    `json:"anonymized_at,omitempty" bson:"anonymized_at,omitempty"` //personal data erased, only the id is left
+	Actor             *Actor        `json:"-" bson:"-" structs:"-"`                                 //who is changing the account, for the audit trail
 }
 
 type UserToken struct {
@@ -44,33 +47,6 @@ type UserToken struct {
 	Expire   int64  `json:"expire"`
 }
 
-func (u *User) FieldMap(req *http.Request) binding.FieldMap {
-	return binding.FieldMap{
-		&u.Email: binding.Field{
-			Form:     "email",
-			Required: true,
-		},
-		&u.Password: binding.Field{
-			Form:     "password",
-			Required: true,
-		},
-		&u.Role:        "role",
-		&u.DisplayName: "display_name",
-		&u.GivenName:   "given_name",
-		&u.FamilyName:  "family_name",
-		&u.Language:    "language",
-		&u.Avatar:      "avatar",
-		&u.ShortBio:    "short_bio",
-		&u.LongBio:     "long_bio",
-		&u.Country:     "country",
-		&u.Phone:       "phone",
-		&u.Birth:       "birth",
-		&u.Age:         "age",
-		&u.Gender:      "gender",
-		&u.Social:      "social",
-	}
-}
-
 // UserSocial is a place to put social details per user. These are the
 // standard keys that themes will expect to have available, but can be
 // expanded to any others on a per site basis
@@ -103,16 +79,23 @@ type LoginStatus struct {
 	Location string    `json:"location"`
 }
 
+// Billing is the payment method of a user. The card number and CID are only
+// accepted on input and handed to the payment gateway, the store keeps the
+// gateway token and the last four digits.
 type Billing struct {
-	CardNumber  string `json:"card_number"`
-	Cid         string `json:"cid"`
-	Expire      string `json:"expire"` //MMYY
-	Type        string `json:"type"`
-	DisplayName string `json:"display_name"`
-	GivenName   string `json:"given_name"`
-	FamilyName  string `json:"family_name"`
-	Country     string `json:"country"`
-	Address     string `json:"address"`
+	ID          bson.ObjectId `json:"-" bson:"_id,omitempty"` //owner user id
+	CardNumber  string        `json:"card_number" bson:"-"`   //masked in responses
+	Cid         string        `json:"cid,omitempty" bson:"-"` //never stored
+	Token       string        `json:"-" bson:"token"`
+	Last4       string        `json:"last4" bson:"last4"`
+	Expire      string        `json:"expire"` //MMYY
+	Type        string        `json:"type"`   //card brand, detected from the number
+	DisplayName string        `json:"display_name" bson:"display_name"`
+	GivenName   string        `json:"given_name" bson:"given_name" encrypt:"true"`
+	FamilyName  string        `json:"family_name" bson:"family_name" encrypt:"true"`
+	Country     string        `json:"country"`
+	Address     string        `json:"address" encrypt:"true"`
+	UpdatedAt   time.Time     `json:"updated_at" bson:"updated_at"`
 }
 
 type Social struct {
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"gopkg.in/mgo.v2/bson"
+	"time"
+)
+
+// Webhook is a subscription to user lifecycle events. Subscriptions come from
+// the [webhook] section of the config or from the admin API.
+type Webhook struct {
+	ID        bson.ObjectId `json:"id,omitempty" bson:"_id,omitempty"`
+	URL       string        `json:"url" valid:"url,required"`
+	Secret    string        `json:"secret,omitempty" valid:"required" secret:"true"`
+	Events    []string      `json:"events"` //empty means every event
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+}
+
+// WebhookPayload is the JSON body posted to subscribers.
+type WebhookPayload struct {
+	ID    string      `json:"id"`
+	Event string      `json:"event"`
+	When  time.Time   `json:"when"`
+	Data  interface{} `json:"data"`
+}
+
+// Delivery is one event sent to one subscriber, kept as the delivery log for
+// the configured retention.
+type Delivery struct {
+	ID          bson.ObjectId `json:"id" bson:"_id,omitempty"`
+	WebhookID   bson.ObjectId `json:"webhook_id,omitempty" bson:"webhook_id,omitempty"` //empty for config subscriptions
+	UserID      bson.ObjectId `json:"user_id,omitempty" bson:"user_id,omitempty"`       //account the event is about
+	URL         string        `json:"url"`
+	Event       string        `json:"event"`
+	Payload     string        `json:"payload,omitempty" bson:"payload,omitempty" encrypt:"true"` //dropped once delivered
+	Status      string        `json:"status"`                                                    //pending,sending,retrying,delivered,dead
+	Attempts    int           `json:"attempts"`
+	LastStatus  int           `json:"last_status,omitempty" bson:"last_status,omitempty"`
+	LastError   string        `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	NextAttempt time.Time     `json:"next_attempt" bson:"next_attempt"`
+	CreatedAt   time.Time     `json:"created_at" bson:"created_at"`
+	DeliveredAt time.Time     `json:"delivered_at,omitempty" bson:"delivered_at,omitempty"`
+}
@@ -0,0 +1,106 @@
+package vibe
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/Festum/Vibe/controllers"
+	"github.com/Festum/Vibe/models"
+	"github.com/Festum/Vibe/utils"
+	"github.com/labstack/echo"
+	"github.com/labstack/echo/engine"
+	"github.com/labstack/echo/engine/standard"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownTimeout bounds the drain of in-flight requests when
+// http.shutdowntimeout is not configured.
+const DefaultShutdownTimeout = 15 * time.Second
+
+// Run serves e on the address of the profile in use until SIGINT or
+// SIGTERM, over TLS when [http.tls] has a certificate. Renewed certificate
+// files are picked up while running, and with a client_ca, services present
+// client certificates instead of tokens. On a signal, in-flight requests are
+// drained for up to http.shutdowntimeout seconds, then the database pool is
+// closed.
+func Run(e *echo.Echo) error {
+	conf := models.Conf()
+	addr := conf.Server().Addr()
+	ln, err := listen(addr, conf)
+	if err != nil {
+		return err
+	}
+
+	s := standard.WithConfig(engine.Config{Address: addr, Listener: ln})
+	done := make(chan error, 1)
+	go func() {
+		done <- e.Run(s)
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	select {
+	case err = <-done:
+	case <-stop:
+		timeout := conf.HTTP.ShutdownTimeout * time.Second
+		if timeout <= 0 {
+			timeout = DefaultShutdownTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		err = s.Shutdown(ctx)
+		if serr := <-done; err == nil && serr != http.ErrServerClosed {
+			err = serr
+		}
+	}
+
+	controllers.CloseDB()
+	return err
+}
+
+// listen opens the TCP listener of addr, wrapped in TLS as configured.
+func listen(addr string, conf *models.Config) (net.Listener, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil || conf.HTTP.TLS.Cert == "" {
+		return ln, err
+	}
+
+	t := conf.HTTP.TLS
+	certs, err := utils.NewCertReloader(t.Cert, t.Key)
+	if err != nil {
+		ln.Close()
+		return nil, err
+	}
+	certs.OnError = func(err error) {
+		logger := new(utils.Logger)
+		logger.Init()
+		logger.Error(map[string]interface{}{
+			"section": "TLS",
+			"cert":    t.Cert,
+			"time":    time.Now(),
+		}, err.Error())
+	}
+
+	config := &tls.Config{
+		GetCertificate: certs.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+	if t.ClientCA != "" {
+		if config.ClientCAs, err = t.ClientCAs(); err != nil {
+			ln.Close()
+			return nil, err
+		}
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if t.ClientAuth == "required" {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return tls.NewListener(ln, config), nil
+}
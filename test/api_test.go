@@ -0,0 +1,136 @@
+package controllers_test
+
+import (
+	"../controllers"
+	"../models"
+	"../wrappers"
+	"encoding/json"
+	"github.com/labstack/echo"
+	"github.com/labstack/echo/engine/standard"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// serve sends a JSON request to the routes of Vibe, with token as bearer
+// when not empty.
+func serve(method, path, token, body string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.SetHTTPErrorHandler(new(wrappers.Handlers).HandleError)
+	new(wrappers.Handlers).Mount(e)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(standard.NewRequest(req, nil), standard.NewResponse(rec, nil))
+	return rec
+}
+
+// collection opens a collection of the test database, for the state the API
+// cannot set up. Close the returned session.
+func collection(t *testing.T, name string) (*mgo.Session, *mgo.Collection) {
+	s, err := mgo.Dial(os.Getenv("MONGO_PORT_27017_TCP_ADDR") + ":" + os.Getenv("MONGO_PORT_27017_TCP_PORT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table := models.Conf().DB.Table[name]; table != "" {
+		name = table
+	}
+	return s, s.DB(models.Conf().DB.Name).C(name)
+}
+
+// dropUser removes the account of username for good, if any.
+func dropUser(username string) {
+	(&controllers.User{Username: username}).Delete()
+	(&controllers.User{Username: username}).Purge()
+}
+
+// login creates the account and returns a token of it.
+func login(t *testing.T, u *controllers.User) string {
+	dropUser(u.Username)
+	if err := u.Create(); err != nil {
+		t.Fatal(err)
+	}
+	body, _ := json.Marshal(map[string]string{"username": u.Username, "password": u.Password})
+	rec := serve(echo.POST, "/login", "", string(body))
+	if rec.Code != http.StatusOK {
+		t.Fatal(rec.Body.String())
+	}
+	var res struct {
+		Token string `json:"token"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &res)
+	return res.Token
+}
+
+func TestAdminUpdatesAccount(t *testing.T) {
+	assert := assert.New(t)
+
+	admin := &controllers.User{Email: "test_admin@vibe.me", Username: "TEST_ADMIN_001", Password: "just_a_pass_123", Role: "admin"}
+	member := &controllers.User{Email: "test_member@vibe.me", Username: "TEST_MEMBER_001", Password: "just_a_pass_123", Role: "member", GivenName: "RT"}
+	adminToken := login(t, admin)
+	memberToken := login(t, member)
+	defer dropUser(admin.Username)
+	defer dropUser(member.Username)
+
+	rec := serve(echo.POST, "/account/update", adminToken, `{"username":"TEST_MEMBER_001","given_name":"TEST1"}`)
+	assert.Equal(http.StatusOK, rec.Code, rec.Body.String())
+	u := &controllers.User{Username: member.Username}
+	assert.Nil(u.Get())
+	assert.Equal("TEST1", u.GivenName)
+	assert.Equal("member", u.Role)
+
+	// A member cannot change another account.
+	rec = serve(echo.POST, "/account/update", memberToken, `{"username":"TEST_ADMIN_001","given_name":"TEST1"}`)
+	assert.Equal(http.StatusForbidden, rec.Code, rec.Body.String())
+}
+
+func TestRegisterIgnoresRole(t *testing.T) {
+	assert := assert.New(t)
+
+	dropUser("TEST_SELF_ADMIN_001")
+	defer dropUser("TEST_SELF_ADMIN_001")
+	rec := serve(echo.POST, "/register", "", `{"email":"test_self_admin@vibe.me","username":"TEST_SELF_ADMIN_001",`+
+		`"password":"just_a_pass_123","role":"admin","is_disabled":false,"avatar":"https://tracker.example.com/me.png"}`)
+	assert.Equal(http.StatusCreated, rec.Code, rec.Body.String())
+	u := &controllers.User{Username: "TEST_SELF_ADMIN_001"}
+	assert.Nil(u.Get())
+	assert.Empty(u.Avatar)
+
+	rec = serve(echo.POST, "/login", "", `{"username":"TEST_SELF_ADMIN_001","password":"just_a_pass_123"}`)
+	assert.Equal(http.StatusOK, rec.Code, rec.Body.String())
+	var res struct {
+		Token string `json:"token"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &res)
+
+	rec = serve(echo.GET, "/users", res.Token, "")
+	assert.Equal(http.StatusForbidden, rec.Code, rec.Body.String())
+}
+
+func TestAvatarNotWritable(t *testing.T) {
+	assert := assert.New(t)
+
+	member := &controllers.User{Email: "test_member@vibe.me", Username: "TEST_MEMBER_001", Password: "just_a_pass_123", Role: "member"}
+	token := login(t, member)
+	defer dropUser(member.Username)
+
+	// Only uploads set the avatar, an update leaves it.
+	rec := serve(echo.POST, "/account/update", token, `{"username":"TEST_MEMBER_001","avatar":"https://tracker.example.com/me.png"}`)
+	assert.Equal(http.StatusOK, rec.Code, rec.Body.String())
+	u := &controllers.User{Username: member.Username}
+	assert.Nil(u.Get())
+	assert.Empty(u.Avatar)
+
+	assert.NotNil(u.Patch(controllers.MergePatchType, []byte(`{"avatar":"https://tracker.example.com/me.png"}`), false))
+	assert.NotNil(u.Patch(controllers.JSONPatchType, []byte(`[{"op":"add","path":"/avatar","value":"https://tracker.example.com/me.png"}]`), false))
+	assert.Nil(u.Get())
+	assert.Empty(u.Avatar)
+}
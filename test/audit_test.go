@@ -0,0 +1,97 @@
+package controllers_test
+
+import (
+	"../controllers"
+	"../models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// auditChain returns n linked entries, as RecordAudit appends them.
+func auditChain(t *testing.T, n int) []models.AuditEntry {
+	entries := make([]models.AuditEntry, n)
+	prev := ""
+	for i := range entries {
+		e := &entries[i]
+		e.Seq, e.PrevHash = int64(i+1), prev
+		e.Action, e.Target = controllers.AuditUpdate, "TEST_USER_001"
+		e.Actor = models.Actor{Username: "TEST_ADMIN_001", Source: "jwt"}
+		e.Changes = []models.FieldChange{{Field: "role", Before: "guest", After: "member"}}
+		e.When = time.Date(2026, 1, 1, 0, 0, i, 0, time.UTC)
+
+		var err error
+		if e.Hash, err = controllers.AuditHash(e); err != nil {
+			t.Fatal(err)
+		}
+		prev = e.Hash
+	}
+	return entries
+}
+
+func verifyChain(entries []models.AuditEntry) (int64, error) {
+	chain := new(controllers.AuditChain)
+	for i := range entries {
+		if err := chain.Next(&entries[i]); err != nil {
+			return chain.Checked, err
+		}
+	}
+	return chain.Checked, nil
+}
+
+func TestAuditHash(t *testing.T) {
+	assert := assert.New(t)
+
+	e := auditChain(t, 1)[0]
+	hash, err := controllers.AuditHash(&e)
+	assert.Nil(err)
+	assert.Equal(e.Hash, hash)
+	assert.Len(hash, 64)
+
+	// The id and the hash itself are not covered, every other field is.
+	e.ID, e.Hash = "anything", ""
+	hash, _ = controllers.AuditHash(&e)
+	assert.Equal(auditChain(t, 1)[0].Hash, hash)
+
+	for _, tamper := range []func(*models.AuditEntry){
+		func(e *models.AuditEntry) { e.Seq++ },
+		func(e *models.AuditEntry) { e.Action = controllers.AuditRole },
+		func(e *models.AuditEntry) { e.Actor.Username = "someone" },
+		func(e *models.AuditEntry) { e.Target = "someone" },
+		func(e *models.AuditEntry) { e.Changes[0].After = "admin" },
+		func(e *models.AuditEntry) { e.Reason = "why not" },
+		func(e *models.AuditEntry) { e.IP = "10.0.0.1" },
+		func(e *models.AuditEntry) { e.When = e.When.Add(time.Millisecond) },
+		func(e *models.AuditEntry) { e.PrevHash = "00" },
+	} {
+		e := auditChain(t, 1)[0]
+		tamper(&e)
+		hash, _ := controllers.AuditHash(&e)
+		assert.NotEqual(e.Hash, hash)
+	}
+}
+
+func TestAuditChain(t *testing.T) {
+	assert := assert.New(t)
+
+	n, err := verifyChain(auditChain(t, 5))
+	assert.Nil(err)
+	assert.Equal(int64(5), n)
+
+	entries := auditChain(t, 5)
+	entries[2].Changes[0].After = "admin"
+	n, err = verifyChain(entries)
+	assert.EqualError(err, "AUDIT_CHAIN_BROKEN: entry 3 has been altered")
+	assert.Equal(int64(2), n)
+
+	// Rehashing an altered entry does not help, the next one no longer follows.
+	entries[2].Hash, _ = controllers.AuditHash(&entries[2])
+	n, err = verifyChain(entries)
+	assert.EqualError(err, "AUDIT_CHAIN_BROKEN: entry 4 does not follow its predecessor")
+	assert.Equal(int64(3), n)
+
+	entries = auditChain(t, 5)
+	n, err = verifyChain(append(entries[:1], entries[2:]...))
+	assert.EqualError(err, "AUDIT_CHAIN_BROKEN: entry 2 is missing")
+	assert.Equal(int64(1), n)
+}
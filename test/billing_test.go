@@ -0,0 +1,61 @@
+package controllers_test
+
+import (
+	"../controllers"
+	"../utils"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCardValidation(t *testing.T) {
+	assert := assert.New(t)
+	card := new(utils.Card)
+
+	for number, brand := range map[string]string{
+		"4242 4242 4242 4242": "visa",
+		"5555-5555-5555-4444": "mastercard",
+		"2223003122003222":    "mastercard",
+		"378282246310005":     "amex",
+		"6011111111111117":    "discover",
+		"3566002020360505":    "jcb",
+		"30569309025904":      "diners",
+	} {
+		n := card.Normalize(number)
+		assert.True(card.Luhn(n), number)
+		assert.Equal(brand, card.Brand(n), number)
+	}
+
+	assert.False(card.Luhn("4242424242424241"))
+	assert.False(card.Luhn("42424242424242a2"))
+	assert.False(card.Luhn("0"))
+	assert.Equal("", card.Brand("9999999999999995"))
+
+	now := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+	_, err := card.Expiry("0324", now)
+	assert.Nil(err)
+	_, err = card.Expiry("02/24", now)
+	assert.EqualError(err, "CARD_EXPIRED")
+	_, err = card.Expiry("1324", now)
+	assert.EqualError(err, "BAD_EXPIRY")
+	_, err = card.Expiry("3/24", now)
+	assert.EqualError(err, "BAD_EXPIRY")
+
+	assert.Equal(4, card.CidLength("amex"))
+	assert.Equal("**** **** **** 4242", card.Mask("4242"))
+}
+
+func TestFakeGateway(t *testing.T) {
+	assert := assert.New(t)
+	g := new(controllers.FakeGateway)
+
+	token, err := g.Tokenize("4242424242424242", "123", "1230")
+	assert.Nil(err)
+	assert.Equal("4242", g.Tokens[token])
+
+	_, err = g.Tokenize("4000000000000002", "123", "1230")
+	assert.EqualError(err, "CARD_DECLINED")
+
+	assert.Nil(g.Remove(token))
+	assert.Empty(g.Tokens)
+}
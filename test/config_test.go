@@ -0,0 +1,197 @@
+package controllers_test
+
+import (
+	"../models"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfigLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vibe-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.toml")
+	ioutil.WriteFile(path, []byte(`
+[jwt]
+SigningKey = "secret"
+SigningMethod = "HS256"
+TokenTTL = 2
+
+[logger]
+Level = "info"
+Info = "`+filepath.Join(dir, "log", "info.log")+`"
+`), 0644)
+	defer models.Load("", "")
+
+	os.Setenv("VIBE_JWT_TOKENTTL", "5")
+	os.Setenv("VIBE_PHONE_TYPES", "mobile, fixed_line")
+	defer os.Unsetenv("VIBE_JWT_TOKENTTL")
+	defer os.Unsetenv("VIBE_PHONE_TYPES")
+
+	c, err := models.Load(path, "")
+	assert.Nil(err)
+	assert.Equal(time.Duration(5), c.JWT.TokenTTL)
+	assert.Equal([]string{"mobile", "fixed_line"}, c.Phone.Types)
+	assert.Equal("secret", models.Conf().JWT.SigningKey)
+	assert.Nil(c.Validate())
+	_, err = os.Stat(filepath.Join(dir, "log"))
+	assert.True(os.IsNotExist(err), "The log directory is left to the logger")
+
+	bad := *c
+	bad.JWT.SigningKey = ""
+	bad.JWT.SigningMethod = "none"
+	bad.JWT.ImpersonationTTL = -15
+	bad.Logger.Error = filepath.Join(path, "error.log")
+	bad.HTTP.PublicURL = "vibe.example.com"
+	var cerr *models.ConfigError
+	assert.True(errors.As(bad.Validate(), &cerr))
+	assert.Len(cerr.Problems, 5)
+
+	os.Setenv("VIBE_JWT_TOKENTTL", "soon")
+	_, err = models.Load(path, "")
+	assert.Error(err)
+	_, err = models.Load(filepath.Join(dir, "missing.toml"), "")
+	assert.Error(err)
+}
+
+func TestConfigProfiles(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vibe-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.toml")
+	ioutil.WriteFile(path, []byte(`
+[database]
+host = "localhost"
+name = "vibe"
+
+[jwt]
+TokenTTL = 60
+
+[servers]
+	[servers.production]
+	ip = "10.0.0.1"
+	port = "8080"
+	dc = "eqdc10"
+		[servers.production.database]
+		host = "db.internal"
+		[servers.production.jwt]
+		TokenTTL = 12
+	[servers.beta]
+	port = "3000"
+`), 0644)
+	defer models.Load("", "")
+
+	c, err := models.Load(path, "production")
+	assert.Nil(err)
+	assert.Equal("production", c.Env)
+	assert.Equal("10.0.0.1:8080", c.Server().Addr())
+	assert.Equal("eqdc10", c.Server().DC)
+	assert.Equal("db.internal", c.DB.Host)
+	assert.Equal("vibe", c.DB.Name)
+	assert.Equal(time.Duration(12), c.JWT.TokenTTL)
+
+	os.Setenv("VIBE_DATABASE_HOST", "db.override")
+	defer os.Unsetenv("VIBE_DATABASE_HOST")
+	c, err = models.Load(path, "beta")
+	assert.Nil(err)
+	assert.Equal(":3000", c.Server().Addr())
+	assert.Equal("db.override", c.DB.Host)
+	assert.Equal(time.Duration(60), c.JWT.TokenTTL)
+
+	c, err = models.Load(path, "")
+	assert.Nil(err)
+	assert.Equal(":"+models.DefaultPort, c.Server().Addr())
+
+	_, err = models.Load(path, "staging")
+	assert.EqualError(err, `unknown environment profile "staging", configured: beta, production`)
+}
+
+type staticSecrets map[string]string
+
+func (s staticSecrets) Resolve(ref string) (string, error) {
+	if v, ok := s[ref]; ok {
+		return v, nil
+	}
+	return "", errors.New("no secret " + ref)
+}
+
+func TestConfigSecrets(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vibe-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keyFile := filepath.Join(dir, "jwt")
+	ioutil.WriteFile(keyFile, []byte("from-file\n"), 0600)
+	path := filepath.Join(dir, "config.toml")
+	write := func(password string) {
+		ioutil.WriteFile(path, []byte(`
+[database]
+password = "`+password+`"
+
+[jwt]
+SigningKey = "file:`+keyFile+`"
+
+[crypto]
+	[crypto.keys]
+	k1 = "test:k1"
+
+[social.github]
+key = "literal"
+`), 0644)
+	}
+	models.RegisterSecretProvider("test", staticSecrets{"k1": "from-provider"})
+	os.Setenv("VIBE_TEST_DB_PASSWORD", "from-env")
+	defer os.Unsetenv("VIBE_TEST_DB_PASSWORD")
+	defer models.Load("", "")
+
+	write("env:VIBE_TEST_DB_PASSWORD")
+	c, err := models.Load(path, "")
+	assert.Nil(err)
+	assert.Equal("from-file", c.JWT.SigningKey)
+	assert.Equal("from-env", c.DB.Password)
+	assert.Equal("from-provider", c.Crypto.Keys["k1"])
+	assert.Equal("literal", c.Social["github"].Key)
+
+	masked := c.Masked()
+	assert.Equal(models.Masked, masked.JWT.SigningKey)
+	assert.Equal(models.Masked, masked.Crypto.Keys["k1"])
+	assert.Equal(models.Masked, masked.Social["github"].Key)
+	assert.Equal("", masked.Social["github"].Secret)
+	assert.Equal("from-provider", c.Crypto.Keys["k1"])
+
+	write("env:VIBE_TEST_UNSET")
+	_, err = models.Load(path, "")
+	assert.EqualError(err, "invalid configuration: database.password: environment variable VIBE_TEST_UNSET is not set")
+}
+
+func TestTrustedProxies(t *testing.T) {
+	assert := assert.New(t)
+
+	c := new(models.Config)
+	c.JWT.SigningKey, c.JWT.SigningMethod = "secret", "HS256"
+	c.HTTP.TrustedProxies = []string{"127.0.0.1", "10.0.0.0/8"}
+	assert.Nil(c.Validate())
+	assert.True(c.HTTP.TrustsProxy("127.0.0.1"))
+	assert.True(c.HTTP.TrustsProxy("10.2.3.4"))
+	assert.False(c.HTTP.TrustsProxy("192.168.1.1"))
+	assert.False(c.HTTP.TrustsProxy("not an address"))
+
+	c.HTTP.TrustedProxies = []string{"10.0.0.0/33"}
+	assert.Error(c.Validate())
+}
@@ -0,0 +1,65 @@
+package controllers_test
+
+import (
+	"../utils"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCountryLookup(t *testing.T) {
+	assert := assert.New(t)
+	c := new(utils.Country)
+
+	assert.Len(c.All(), 249)
+	for _, code := range []string{"FR", "fr", "FRA", "250"} {
+		info, ok := c.Lookup(code)
+		assert.True(ok, code)
+		assert.Equal("FR", info.Alpha2, code)
+		assert.Equal("33", info.Calling, code)
+	}
+	info, ok := c.Lookup("4")
+	assert.True(ok)
+	assert.Equal("AF", info.Alpha2)
+	_, ok = c.Lookup("XX")
+	assert.False(ok)
+
+	assert.True(c.IsValid("DE"))
+	assert.False(c.IsValid("DEU"))
+	assert.False(c.IsValid("de"))
+
+	assert.Equal("Germany", c.Name("DE", "en"))
+	assert.Equal("Allemagne", c.Name("DE", "fr-CA"))
+	assert.Equal("Germany", c.Name("DE", "nl"))
+
+	// Unknown codes no longer panic.
+	assert.Equal("", c.Iso2Country("ZZ"))
+	assert.Equal("Japan", c.Iso2Country("jp"))
+}
+
+func TestCountryMatch(t *testing.T) {
+	assert := assert.New(t)
+	c := new(utils.Country)
+
+	for name, code := range map[string]string{
+		"Japan":          "JP",
+		"côte d'ivoire":  "CI",
+		"COTE D’IVOIRE":  "CI",
+		"Deutschland":    "DE",
+		"the Bahamas":    "BS",
+		"Saint Lucia":    "LC",
+		"UK":             "GB",
+		"Czech Republic": "CZ",
+		"Switzerlnd":     "CH",
+		"Argentinia":     "AR",
+	} {
+		info, ok := c.Match(name)
+		assert.True(ok, name)
+		assert.Equal(code, info.Alpha2, name)
+	}
+
+	for _, name := range []string{"", "Atlantis", "Xyz"} {
+		_, ok := c.Match(name)
+		assert.False(ok, name)
+	}
+	assert.Equal("BR", c.Country2Iso("Brasil"))
+}
@@ -0,0 +1,30 @@
+package controllers_test
+
+import (
+	"../controllers"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrorKinds(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(errors.Is(controllers.ErrVersionConflict, controllers.ErrConflict))
+	assert.True(errors.Is(controllers.ErrAccountExists, controllers.ErrConflict))
+	assert.False(errors.Is(controllers.ErrAccountExists, controllers.ErrNotFound))
+	assert.EqualError(controllers.ErrVersionConflict, "VERSION_CONFLICT")
+
+	_, err := new(controllers.FakeGateway).Tokenize("4000000000000002", "123", "1230")
+	assert.True(errors.Is(err, controllers.ErrBadRequest))
+
+	verr := new(controllers.ValidationError)
+	assert.Nil(verr.Err())
+	verr.Add("email", "INVALID_EMAIL")
+	err = verr.Err()
+	assert.True(errors.Is(err, controllers.ErrValidation))
+	var found *controllers.ValidationError
+	assert.True(errors.As(err, &found))
+	assert.Equal("INVALID_EMAIL", found.Fields[0].Code)
+	assert.Equal("must be a valid email address", found.Fields[0].Reason)
+}
@@ -0,0 +1,41 @@
+package controllers_test
+
+import (
+	"../controllers"
+	"../models"
+	"../utils"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportLinkKey(t *testing.T) {
+	assert := assert.New(t)
+
+	// A link signed with the signing key itself is refused.
+	id := bson.NewObjectId()
+	expires := time.Now().Add(time.Hour)
+	signer := &utils.WebhookSigner{Secret: models.Conf().JWT.SigningKey}
+	sig := strings.SplitN(signer.Sign(expires, []byte(id.Hex())), ",v1=", 2)[1]
+	_, _, err := controllers.OpenExport(id.Hex(), strconv.FormatInt(expires.Unix(), 10), sig)
+	assert.EqualError(err, "BAD_SIGNATURE")
+}
+
+func TestExportURL(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(public string) { models.Conf().HTTP.PublicURL = public }(models.Conf().HTTP.PublicURL)
+	e := &models.Export{ID: bson.NewObjectId(), ExpiresAt: time.Now().Add(time.Hour)}
+
+	models.Conf().HTTP.PublicURL = ""
+	_, err := controllers.ExportURL(e)
+	assert.NotNil(err, "A relative link is useless in an email")
+
+	models.Conf().HTTP.PublicURL = "https://vibe.example.com/"
+	link, err := controllers.ExportURL(e)
+	assert.Nil(err)
+	assert.Equal("https://vibe.example.com"+controllers.ExportLink(e), link)
+}
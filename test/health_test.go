@@ -0,0 +1,41 @@
+package controllers_test
+
+import (
+	"../controllers"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestReady(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(d time.Duration) { controllers.HealthCheckTimeout = d }(controllers.HealthCheckTimeout)
+	controllers.HealthCheckTimeout = 50 * time.Millisecond
+
+	defer controllers.UnregisterHealthCheck("relay")
+	defer controllers.UnregisterHealthCheck("slow")
+	controllers.RegisterHealthCheck("relay", func() error { return errors.New("relay down") })
+	controllers.RegisterHealthCheck("slow", func() error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	controllers.RegisterHealthCheck("relay", func() error { return nil })
+
+	r := controllers.Ready()
+	assert.Equal(controllers.CheckFailed, r.Status)
+	byName := map[string]controllers.CheckResult{}
+	for _, c := range r.Checks {
+		byName[c.Name] = c
+	}
+	assert.Len(r.Checks, 5)
+	assert.Equal("database", r.Checks[0].Name)
+	assert.Equal(controllers.CheckOK, byName["relay"].Status)
+	assert.Equal(controllers.CheckFailed, byName["slow"].Status)
+	assert.Contains(byName["slow"].Error, "no answer")
+	assert.True(byName["slow"].Latency >= 50)
+
+	controllers.UnregisterHealthCheck("slow")
+	assert.Len(controllers.Ready().Checks, 4)
+}
@@ -0,0 +1,50 @@
+package controllers_test
+
+import (
+	"../utils"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	assert := assert.New(t)
+	c := &utils.Catalog{Default: "en"}
+	c.Add("en", map[string]string{
+		"NOT_FOUND": "Not found.",
+		"WELCOME":   "Welcome {{.Username}}!",
+		"BAD_CID":   "must be {{.Digits}} digits",
+	})
+	c.Add("fr", map[string]string{
+		"NOT_FOUND": "Introuvable.",
+		"WELCOME":   "Bienvenue {{.Username}} !",
+	})
+	c.Add("pt_BR", map[string]string{"NOT_FOUND": "Não encontrado."})
+
+	assert.Equal([]string{"en", "fr", "pt-BR"}, c.Languages())
+	assert.Equal("fr", c.Match("fr-CA,fr;q=0.9,en;q=0.8"))
+	assert.Equal("pt-BR", c.Match("", "pt-BR"))
+	assert.Equal("en", c.Match("xx", "de"))
+	assert.Equal("fr", c.Match("de-DE,fr;q=0.5"))
+
+	assert.Equal("Bienvenue ana !", c.Text("fr-BE", "WELCOME", map[string]interface{}{"Username": "ana"}))
+	assert.Equal("must be 3 digits", c.Text("fr", "BAD_CID", map[string]interface{}{"Digits": 3}))
+	assert.Equal("Welcome !", c.Text("pt-BR", "WELCOME", nil))
+	assert.Equal("NO_SUCH_CODE", c.Text("fr", "NO_SUCH_CODE", nil))
+	_, err := c.Render("fr", "NO_SUCH_CODE", nil)
+	assert.EqualError(err, "MESSAGE_NOT_FOUND")
+
+	assert.True(c.Has("BAD_CID"))
+	assert.False(c.Has("NO_SUCH_CODE"))
+	assert.Equal([]string{"BAD_CID"}, c.Missing("fr"))
+}
+
+func TestCatalogLoadDir(t *testing.T) {
+	assert := assert.New(t)
+	c := &utils.Catalog{Default: "en"}
+	assert.NoError(c.LoadDir("../locales"))
+	assert.Contains(c.Languages(), "fr")
+	assert.Contains(c.Languages(), "de")
+	assert.Equal("Introuvable.", c.Text("fr-FR", "NOT_FOUND", nil))
+	assert.Equal("muss 4 Ziffern haben", c.Text("de", "BAD_CID", map[string]interface{}{"Digits": 4}))
+	assert.Equal("es", c.Match("es-MX"))
+}
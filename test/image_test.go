@@ -0,0 +1,144 @@
+package controllers_test
+
+import (
+	"../utils"
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+var (
+	red   = color.RGBA{255, 0, 0, 255}
+	green = color.RGBA{0, 255, 0, 255}
+	blue  = color.RGBA{0, 0, 255, 255}
+	white = color.RGBA{255, 255, 255, 255}
+)
+
+// quadrants is a 32x16 picture, red, green, blue and white from the top left
+// corner, so every flip and rotation shows another color there.
+func quadrants() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 32; x++ {
+			c := [2][2]color.RGBA{{red, green}, {blue, white}}[y/8][x/16]
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// exifJPEG encodes img as a JPEG with an EXIF orientation in the byte order
+// "II" or "MM".
+func exifJPEG(t *testing.T, img image.Image, order string, orientation uint16) []byte {
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	var bo binary.ByteOrder = binary.LittleEndian
+	if order == "MM" {
+		bo = binary.BigEndian
+	}
+
+	tiff := make([]byte, 26)
+	copy(tiff, order)
+	bo.PutUint16(tiff[2:], 42)
+	bo.PutUint32(tiff[4:], 8) // first IFD
+	bo.PutUint16(tiff[8:], 1) // one entry
+	bo.PutUint16(tiff[10:], 0x0112)
+	bo.PutUint16(tiff[12:], 3) // SHORT
+	bo.PutUint32(tiff[14:], 1)
+	bo.PutUint16(tiff[18:], orientation)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	data := buf.Bytes()
+	return append(append([]byte{0xFF, 0xD8}, app1...), data[2:]...)
+}
+
+// near tells if c is within the JPEG losses of want.
+func near(c color.Color, want color.RGBA) bool {
+	r, g, b, _ := c.RGBA()
+	for _, d := range []int{int(r>>8) - int(want.R), int(g>>8) - int(want.G), int(b>>8) - int(want.B)} {
+		if d < -48 || d > 48 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestImageOrientation(t *testing.T) {
+	assert := assert.New(t)
+	m := new(utils.Imager)
+
+	for _, c := range []struct {
+		order       string
+		orientation uint16
+		width       int
+		topLeft     color.RGBA
+	}{
+		{"II", 1, 32, red},
+		{"II", 2, 32, green},
+		{"II", 3, 32, white},
+		{"II", 4, 32, blue},
+		{"II", 5, 16, red},
+		{"II", 6, 16, blue},
+		{"II", 7, 16, white},
+		{"II", 8, 16, green},
+		{"MM", 6, 16, blue},
+		{"MM", 8, 16, green},
+		{"II", 9, 32, red}, // out of range, left as it is
+		{"XX", 6, 32, red}, // unknown byte order
+	} {
+		img, err := m.Decode(exifJPEG(t, quadrants(), c.order, c.orientation))
+		if !assert.Nil(err) {
+			continue
+		}
+		b := img.Bounds()
+		assert.Equal(c.width, b.Dx(), "%s %d", c.order, c.orientation)
+		assert.Equal(48-c.width, b.Dy(), "%s %d", c.order, c.orientation)
+		assert.True(near(img.At(b.Min.X+2, b.Min.Y+2), c.topLeft), "%s %d: top left is %v", c.order, c.orientation, img.At(2, 2))
+	}
+
+	// Without EXIF the picture is kept as encoded.
+	buf := new(bytes.Buffer)
+	jpeg.Encode(buf, quadrants(), &jpeg.Options{Quality: 100})
+	img, err := m.Decode(buf.Bytes())
+	assert.Nil(err)
+	assert.Equal(32, img.Bounds().Dx())
+}
+
+func TestImageLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	png.Encode(buf, quadrants())
+	data := buf.Bytes()
+
+	for _, c := range []struct {
+		imager *utils.Imager
+		data   []byte
+		err    string
+	}{
+		{&utils.Imager{}, data, ""},
+		{&utils.Imager{MaxPixels: 32 * 16}, data, ""},
+		{&utils.Imager{MaxPixels: 32*16 - 1}, data, "IMAGE_TOO_LARGE"},
+		{&utils.Imager{MaxBytes: int64(len(data)) - 1}, data, "IMAGE_TOO_LARGE"},
+		{&utils.Imager{}, []byte("not a picture at all"), "UNSUPPORTED_IMAGE"},
+		{&utils.Imager{}, data[:len(data)/2], "BAD_IMAGE"},
+	} {
+		_, err := c.imager.Decode(c.data)
+		if c.err == "" {
+			assert.Nil(err)
+		} else {
+			assert.EqualError(err, c.err)
+		}
+	}
+}
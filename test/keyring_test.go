@@ -0,0 +1,80 @@
+package controllers_test
+
+import (
+	"../utils"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func testKeyring() *utils.Keyring {
+	return &utils.Keyring{
+		Keys: map[string][]byte{
+			"k1": []byte(strings.Repeat("1", 32)),
+			"k2": []byte(strings.Repeat("2", 32)),
+		},
+		Primary:  "k1",
+		IndexKey: []byte(strings.Repeat("i", 32)),
+	}
+}
+
+func TestKeyringSealOpen(t *testing.T) {
+	assert := assert.New(t)
+	k := testKeyring()
+	assert.Nil(k.Check())
+
+	sealed, err := k.Seal([]byte(`"+886912345678"`), []byte("phone"))
+	assert.Nil(err)
+	assert.True(k.IsSealed(sealed))
+	assert.Equal("k1", k.KeyID(sealed))
+	assert.NotContains(sealed, "886912345678")
+
+	plain, err := k.Open(sealed, []byte("phone"))
+	assert.Nil(err)
+	assert.Equal(`"+886912345678"`, string(plain))
+
+	// The field name is bound to the value.
+	_, err = k.Open(sealed, []byte("birth"))
+	assert.EqualError(err, "DECRYPT_FAILED")
+
+	// So are the collection and the document.
+	ad := utils.FieldAD("user", "5f0c0000000000000000000a", "phone")
+	sealed, _ = k.Seal([]byte(`"+886912345678"`), ad)
+	_, err = k.Open(sealed, ad)
+	assert.Nil(err)
+	for _, other := range [][]byte{
+		utils.FieldAD("user", "5f0c0000000000000000000b", "phone"),
+		utils.FieldAD("billing", "5f0c0000000000000000000a", "phone"),
+		utils.FieldAD("user", "5f0c0000000000000000000a", "given_name"),
+	} {
+		_, err = k.Open(sealed, other)
+		assert.EqualError(err, "DECRYPT_FAILED", string(other))
+	}
+
+	again, _ := k.Seal([]byte(`"+886912345678"`), ad)
+	assert.NotEqual(sealed, again)
+	assert.Equal(k.BlindIndex("+886912345678"), k.BlindIndex("+886912345678"))
+	assert.NotEqual(k.BlindIndex("+886912345678"), k.BlindIndex("+886912345679"))
+}
+
+func TestKeyringRotation(t *testing.T) {
+	assert := assert.New(t)
+	k := testKeyring()
+
+	sealed, _ := k.Seal([]byte("secret"), []byte("address"))
+	k.Primary = "k2"
+	rotated, err := k.Rewrap(sealed)
+	assert.Nil(err)
+	assert.Equal("k2", k.KeyID(rotated))
+
+	// The old key can go once every value is rewrapped.
+	delete(k.Keys, "k1")
+	plain, err := k.Open(rotated, []byte("address"))
+	assert.Nil(err)
+	assert.Equal("secret", string(plain))
+	_, err = k.Open(sealed, []byte("address"))
+	assert.EqualError(err, "KEYRING_UNKNOWN_KEY")
+
+	k.Keys["k3"] = []byte("short")
+	assert.EqualError(k.Check(), "KEYRING_BAD_KEY")
+}
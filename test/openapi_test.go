@@ -0,0 +1,68 @@
+package controllers_test
+
+import (
+	"../wrappers"
+	"encoding/json"
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIUpToDate(t *testing.T) {
+	want, err := ioutil.ReadFile("../docs/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(new(wrappers.Handlers).Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, string(want), string(got), "docs/openapi.json is stale, run: vibecli openapi --out docs/openapi.json")
+}
+
+func TestRoutesCoverHandlers(t *testing.T) {
+	h := new(wrappers.Handlers)
+	routed, seen := map[string]bool{}, map[string]bool{}
+	for _, r := range h.Routes() {
+		if seen[r.Method+" "+r.Path] {
+			t.Errorf("%s %s is routed twice", r.Method, r.Path)
+		}
+		seen[r.Method+" "+r.Path] = true
+
+		name := runtime.FuncForPC(reflect.ValueOf(r.Handler).Pointer()).Name()
+		routed[strings.TrimSuffix(name[strings.LastIndex(name, ".")+1:], "-fm")] = true
+	}
+
+	// Placeholders without an endpoint yet.
+	unrouted := map[string]bool{"Social": true, "Accessible": true}
+	handler := reflect.TypeOf(echo.HandlerFunc(nil))
+	methods := reflect.TypeOf(h)
+	for i := 0; i < methods.NumMethod(); i++ {
+		m := methods.Method(i)
+		if m.Type.NumIn() != 2 || m.Type.NumOut() != 1 ||
+			m.Type.In(1) != handler.In(0) || m.Type.Out(0) != handler.Out(0) {
+			continue
+		}
+		if !routed[m.Name] && !unrouted[m.Name] {
+			t.Errorf("handler %s has no route, add it to Handlers.Routes", m.Name)
+		}
+	}
+}
+
+func TestEnabledRoutes(t *testing.T) {
+	h := &wrappers.Handlers{Prefix: "/members", Disabled: map[string]bool{wrappers.GroupAdmin: true}}
+	routes := h.Enabled()
+	assert.NotEmpty(t, routes)
+	for _, r := range routes {
+		assert.NotEqual(t, wrappers.GroupAdmin, r.Tag, r.Path)
+		assert.True(t, strings.HasPrefix(r.Path, "/members/"), r.Path)
+	}
+
+	paths := h.Spec()["paths"].(map[string]interface{})
+	assert.Contains(t, paths, "/members/login")
+	assert.NotContains(t, paths, "/members/users")
+}
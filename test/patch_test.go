@@ -0,0 +1,73 @@
+package controllers_test
+
+import (
+	"../utils"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestMergePatch(t *testing.T) {
+	assert := assert.New(t)
+	p := new(utils.Patch)
+
+	// RFC 7396 section 3
+	target := decodeJSON(t, `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`)
+	patched, err := p.Merge(target, []byte(`{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`))
+	assert.Nil(err)
+	assert.Equal(decodeJSON(t, `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`), patched)
+	assert.Equal("Goodbye!", target.(map[string]interface{})["title"], "Target must not be modified")
+
+	patched, err = p.Merge(decodeJSON(t, `{"short_bio":"hi"}`), []byte(`{"short_bio":""}`))
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{"short_bio": ""}, patched, "Empty string must clear a field")
+
+	_, err = p.Merge(target, []byte(`{`))
+	assert.NotNil(err)
+}
+
+func TestJSONPatch(t *testing.T) {
+	assert := assert.New(t)
+	p := new(utils.Patch)
+	target := decodeJSON(t, `{"baz":"qux","foo":"bar","list":["a","c"],"social":{"github":"vibe"}}`)
+
+	patched, err := p.Apply(target, []byte(`[
+		{"op":"test","path":"/baz","value":"qux"},
+		{"op":"replace","path":"/baz","value":"boo"},
+		{"op":"add","path":"/list/1","value":"b"},
+		{"op":"add","path":"/list/-","value":"d"},
+		{"op":"remove","path":"/foo"},
+		{"op":"copy","from":"/social/github","path":"/social/gitlab"},
+		{"op":"move","from":"/baz","path":"/qux"}
+	]`))
+	assert.Nil(err)
+	assert.Equal(decodeJSON(t, `{"qux":"boo","list":["a","b","c","d"],"social":{"github":"vibe","gitlab":"vibe"}}`), patched)
+
+	_, err = p.Apply(target, []byte(`[{"op":"replace","path":"/baz","value":"x"},{"op":"test","path":"/baz","value":"qux"}]`))
+	assert.EqualError(err, "PATCH_TEST_FAILED")
+	assert.Equal("qux", target.(map[string]interface{})["baz"], "Failed patch must leave target untouched")
+
+	_, err = p.Apply(target, []byte(`[{"op":"remove","path":"/missing"}]`))
+	assert.EqualError(err, "PATH_NOT_FOUND")
+	_, err = p.Apply(target, []byte(`[{"op":"replace","path":"/missing","value":1}]`))
+	assert.EqualError(err, "PATH_NOT_FOUND")
+	_, err = p.Apply(target, []byte(`[{"op":"add","path":"/baz"}]`))
+	assert.EqualError(err, "BAD_PATCH")
+	patched, err = p.Apply(target, []byte(`[{"op":"replace","path":"/baz","value":null},{"op":"test","path":"/baz","value":null}]`))
+	assert.Nil(err, "An explicit null is a value")
+	assert.Nil(patched.(map[string]interface{})["baz"])
+	_, err = p.Apply(target, []byte(`[{"op":"jump","path":"/baz"}]`))
+	assert.EqualError(err, "BAD_PATCH")
+
+	paths, err := p.Paths([]byte(`[{"op":"move","from":"/a~1b","path":"/c"}]`))
+	assert.Nil(err)
+	assert.Equal([]string{"/c", "/a~1b"}, paths)
+}
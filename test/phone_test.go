@@ -0,0 +1,65 @@
+package controllers_test
+
+import (
+	"../utils"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPhoneParse(t *testing.T) {
+	assert := assert.New(t)
+	p := new(utils.Phone)
+
+	for _, c := range []struct {
+		number, region, e164, country, kind string
+	}{
+		{"(415) 555-2671", "US", "+14155552671", "US", utils.PhoneFixedLineOrMobile},
+		{"1-800-555-0199", "CA", "+18005550199", "CA", utils.PhoneTollFree},
+		{"+1 415 555 2671", "", "+14155552671", "US", utils.PhoneFixedLineOrMobile},
+		{"011 44 20 7946 0018", "US", "+442079460018", "GB", utils.PhoneFixedLine},
+		{"07911 123456", "GB", "+447911123456", "GB", utils.PhoneMobile},
+		{"+44 (0)20 7946 0018", "FR", "+442079460018", "GB", utils.PhoneFixedLine},
+		{"06 12 34 56 78", "fr", "+33612345678", "FR", utils.PhoneMobile},
+		{"0033 1 23 45 67 89", "DE", "+33123456789", "FR", utils.PhoneFixedLine},
+		{"06 6982 1234", "IT", "+390669821234", "IT", utils.PhoneFixedLine},
+		{"+7 701 123 4567", "", "+77011234567", "KZ", utils.PhoneMobile},
+		{"8 (912) 345-67-89", "RU", "+79123456789", "RU", utils.PhoneMobile},
+		{"090-1234-5678", "JP", "+819012345678", "JP", utils.PhoneMobile},
+		{"+354 551 2345", "", "+3545512345", "IS", utils.PhoneUnknown},
+	} {
+		n, err := p.Parse(c.number, c.region)
+		if assert.Nil(err, c.number) {
+			assert.Equal(c.e164, n.E164, c.number)
+			assert.Equal(c.country, n.Region, c.number)
+			assert.Equal(c.kind, n.Type, c.number)
+		}
+	}
+
+	for number, code := range map[string]string{
+		"":                    "BAD_PHONE",
+		"415 555 2671 x2":     "BAD_PHONE",
+		"415 555 2671":        "PHONE_REGION_REQUIRED",
+		"+999 1234 5678":      "UNKNOWN_CALLING_CODE",
+		"+1 415 555":          "PHONE_TOO_SHORT",
+		"+33 6 12 34 56 78 9": "PHONE_TOO_LONG",
+	} {
+		_, err := p.Parse(number, "")
+		assert.EqualError(err, code, number)
+	}
+
+	mobile := &utils.Phone{Types: []string{utils.PhoneMobile}}
+	_, err := mobile.Parse("+44 20 7946 0018", "")
+	assert.EqualError(err, "PHONE_TYPE_NOT_ALLOWED")
+	_, err = mobile.Parse("+44 7911 123456", "")
+	assert.Nil(err)
+}
+
+func TestPhoneFormat(t *testing.T) {
+	assert := assert.New(t)
+	p := new(utils.Phone)
+
+	assert.Equal("+1 415-555-2671", p.Format("+14155552671"))
+	assert.Equal("+33 612 345 678", p.Format("+33612345678"))
+	assert.Equal("+44 207 946 0018", p.Format("+442079460018"))
+	assert.Equal("not a phone", p.Format("not a phone"))
+}
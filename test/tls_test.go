@@ -0,0 +1,117 @@
+package controllers_test
+
+import (
+	"../models"
+	"../utils"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert writes a self-signed certificate for name and its key.
+func writeCert(t *testing.T, certFile, keyFile, name string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+	ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+}
+
+func TestCertReloader(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vibe-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeCert(t, certFile, keyFile, "old.example.com")
+
+	defer func(d time.Duration) { utils.CertCheckInterval = d }(utils.CertCheckInterval)
+	utils.CertCheckInterval = 0
+
+	r, err := utils.NewCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var failures []error
+	r.OnError = func(err error) { failures = append(failures, err) }
+	cert, _ := r.GetCertificate(nil)
+	leaf, _ := x509.ParseCertificate(cert.Certificate[0])
+	assert.Equal("old.example.com", leaf.Subject.CommonName)
+
+	writeCert(t, certFile, keyFile, "new.example.com")
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, later, later)
+	cert, _ = r.GetCertificate(nil)
+	leaf, _ = x509.ParseCertificate(cert.Certificate[0])
+	assert.Equal("new.example.com", leaf.Subject.CommonName)
+
+	// A broken renewal keeps the previous certificate.
+	ioutil.WriteFile(keyFile, []byte("not a key"), 0600)
+	later = later.Add(time.Minute)
+	os.Chtimes(keyFile, later, later)
+	cert, _ = r.GetCertificate(nil)
+	leaf, _ = x509.ParseCertificate(cert.Certificate[0])
+	assert.Equal("new.example.com", leaf.Subject.CommonName)
+	assert.NotEmpty(failures)
+}
+
+func TestConfigTLS(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vibe-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeCert(t, certFile, keyFile, "vibe.example.com")
+
+	c := new(models.Config)
+	c.JWT.SigningKey, c.JWT.SigningMethod = "secret", "HS256"
+	c.HTTP.TLS.Cert, c.HTTP.TLS.Key = certFile, keyFile
+	c.HTTP.TLS.ClientCA, c.HTTP.TLS.ClientAuth = certFile, "required"
+	assert.Nil(c.Validate())
+	pool, err := c.HTTP.TLS.ClientCAs()
+	assert.Nil(err)
+	assert.NotNil(pool)
+
+	c.HTTP.TLS.Key = ""
+	c.HTTP.TLS.ClientCA = keyFile
+	c.HTTP.TLS.ClientAuth = "always"
+	c.HTTP.ShutdownTimeout = -1
+	var cerr *models.ConfigError
+	assert.True(errors.As(c.Validate(), &cerr))
+	assert.Len(cerr.Problems, 4)
+}
@@ -5,6 +5,7 @@ import (
 	"../utils"
 	"errors"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
 	"testing"
 	"time"
 )
@@ -37,7 +38,7 @@ func TestUserCRUD(t *testing.T) {
 	}
 	err := u.Create()
 	if err != nil {
-		if err.Error() != "E11000" {
+		if err != controllers.ErrAccountExists {
 			t.Error("User cannot be created: " + err.Error())
 		}
 		u.Delete()
@@ -55,7 +56,7 @@ func TestUserCRUD(t *testing.T) {
 	assert.Equal(u.Phone, u2.Phone, "User profile is not consistent")
 
 	u.GivenName = "TEST1"
-	if err := u.Update(u.Username); err != nil {
+	if err := u.Update(); err != nil {
 		t.Error("User cannot be updated: " + err.Error())
 	}
 	u2.Get()
@@ -64,3 +65,33 @@ func TestUserCRUD(t *testing.T) {
 	err = u.Delete()
 	assert.Nil(err)
 }
+
+func TestRecentLoginSurvivesInactivity(t *testing.T) {
+	assert := assert.New(t)
+
+	u := &controllers.User{Email: "test_inactive@vibe.me", Username: "TEST_INACTIVE_001", Password: "just_a_pass_123", Role: "member"}
+	dropUser(u.Username)
+	defer dropUser(u.Username)
+	if err := u.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Age the account as if it had not been used for years.
+	s, col := collection(t, "user")
+	defer s.Close()
+	var err error
+
+	// The second login is answered from the token cache and counts as well.
+	for i := 0; i < 2; i++ {
+		assert.Nil(col.UpdateId(u.ID, bson.M{"$set": bson.M{"last_login": time.Now().AddDate(-5, 0, 0)}}))
+		_, err = (&controllers.User{Username: u.Username}).GenerateToken("", "", -1)
+		assert.Nil(err)
+
+		_, err = controllers.AnonymizeInactive(2)
+		assert.Nil(err)
+		u2 := &controllers.User{Username: u.Username}
+		assert.Nil(u2.Get())
+		assert.Nil(u2.AnonymizedAt)
+		assert.WithinDuration(time.Now(), u2.LastLogin, time.Minute)
+	}
+}
@@ -0,0 +1,69 @@
+package controllers_test
+
+import (
+	"../controllers"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	u := &controllers.User{
+		Email:    "not-an-email",
+		Username: "a b",
+		Password: "short",
+		Role:     "member",
+		Language: "en-US",
+		Birth:    time.Now().Add(24 * time.Hour),
+		Age:      200,
+	}
+	err := u.Validate(controllers.OpCreate, nil)
+	var verr *controllers.ValidationError
+	assert.True(errors.As(err, &verr))
+	codes := map[string]string{}
+	for _, f := range verr.Fields {
+		codes[f.Field] = f.Code
+	}
+	assert.Equal(map[string]string{
+		"email":    "INVALID_EMAIL",
+		"username": "INVALID_USERNAME",
+		"password": "TOO_SHORT",
+		"birth":    "IN_FUTURE",
+		"age":      "OUT_OF_RANGE",
+	}, codes)
+
+	// Only changed fields are checked on update, and nothing is required
+	// that was not there before.
+	before := *u
+	u.Age = 30
+	assert.Nil(u.Validate(controllers.OpUpdate, &before))
+
+	assert.Nil((&controllers.User{Username: "anon-1f2e"}).ValidateLogin("any password"))
+	err = (&controllers.User{}).ValidateLogin("")
+	assert.True(errors.Is(err, controllers.ErrValidation))
+	assert.Len(err.(*controllers.ValidationError).Fields, 2)
+}
+
+func TestValidateRulesConfigurable(t *testing.T) {
+	assert := assert.New(t)
+
+	create := controllers.Rules[controllers.OpCreate]
+	defer func(rule string) { create["display_name"] = rule }(create["display_name"])
+
+	u := &controllers.User{Email: "a@example.com", Username: "alice", Password: "long enough", Role: "member"}
+	assert.Nil(u.Validate(controllers.OpCreate, nil))
+
+	create["display_name"] = "required,max=3"
+	err := u.Validate(controllers.OpCreate, nil)
+	assert.EqualError(err, "VALIDATION_FAILED: display_name: is required")
+	u.DisplayName = "Alice"
+	err = u.Validate(controllers.OpCreate, nil)
+	assert.EqualError(err, "VALIDATION_FAILED: display_name: is too long, at most 3 characters")
+
+	create["display_name"] = "shiny"
+	assert.NotNil(u.Validate(controllers.OpCreate, nil))
+	assert.False(errors.Is(u.Validate(controllers.OpCreate, nil), controllers.ErrValidation))
+}
@@ -0,0 +1,192 @@
+package controllers_test
+
+import (
+	"../controllers"
+	"../models"
+	"../utils"
+	"encoding/base64"
+	"errors"
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	assert := assert.New(t)
+	secret := "test_webhook_secret"
+
+	var received []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		signer := &utils.WebhookSigner{Secret: secret}
+		if err := signer.Verify(r.Header.Get(controllers.SignatureHeader), body, time.Minute); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(controllers.EventHeader) != controllers.EventRegistered {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	d := &models.Delivery{
+		ID:      bson.NewObjectId(),
+		Event:   controllers.EventRegistered,
+		Payload: `{"event":"user.registered","data":{"username":"TEST_USER_001"}}`,
+	}
+
+	status, err := controllers.DeliverWebhook(models.Webhook{URL: receiver.URL, Secret: secret}, d)
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, status)
+	assert.Equal(d.Payload, string(received), "Payload must be delivered as signed")
+
+	status, err = controllers.DeliverWebhook(models.Webhook{URL: receiver.URL, Secret: "wrong_secret"}, d)
+	assert.NotNil(err, "A rejected delivery must be retried")
+	assert.Equal(http.StatusUnauthorized, status)
+}
+
+func TestWebhookTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(1500 * time.Millisecond)
+	}))
+	defer receiver.Close()
+
+	// The timeout is the one configured when the delivery is sent.
+	defer func(d time.Duration) { models.Conf().Webhook.Timeout = d }(models.Conf().Webhook.Timeout)
+	models.Conf().Webhook.Timeout = 1
+	d := &models.Delivery{ID: bson.NewObjectId(), Event: controllers.EventLogin, Payload: `{}`}
+	start := time.Now()
+	_, err := controllers.DeliverWebhook(models.Webhook{URL: receiver.URL, Secret: "test_webhook_secret"}, d)
+	assert.NotNil(err)
+	assert.True(time.Since(start) < 1400*time.Millisecond)
+}
+
+func TestWebhookSignature(t *testing.T) {
+	assert := assert.New(t)
+	signer := &utils.WebhookSigner{Secret: "test_webhook_secret"}
+	body := []byte(`{"event":"user.deleted"}`)
+
+	header := signer.Sign(time.Now(), body)
+	assert.Nil(signer.Verify(header, body, time.Minute))
+	assert.NotNil(signer.Verify(header, []byte(`{"event":"user.login"}`), time.Minute), "Tampered body must be refused")
+	assert.NotNil(signer.Verify("v1=deadbeef", body, time.Minute), "Missing timestamp must be refused")
+
+	old := signer.Sign(time.Now().Add(-time.Hour), body)
+	assert.NotNil(signer.Verify(old, body, time.Minute), "Replayed delivery must be refused")
+	assert.Nil(signer.Verify(old, body, 0))
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert := assert.New(t)
+
+	first := controllers.WebhookBackoff(1)
+	assert.True(first > 0)
+	assert.Equal(2*first, controllers.WebhookBackoff(2))
+	assert.Equal(4*first, controllers.WebhookBackoff(3))
+	assert.True(controllers.WebhookBackoff(100) <= 6*time.Hour, "Backoff must be capped")
+}
+
+func TestDeriveKey(t *testing.T) {
+	assert := assert.New(t)
+
+	key := utils.DeriveKey("SIGNED_KEY", "export")
+	assert.Len(key, 32)
+	assert.Equal(key, utils.DeriveKey("SIGNED_KEY", "export"))
+	assert.NotEqual(key, utils.DeriveKey("SIGNED_KEY", "other"))
+	assert.NotEqual(key, utils.DeriveKey("OTHER_KEY", "export"))
+	assert.NotEqual("SIGNED_KEY", key)
+}
+
+func TestReplayDelivered(t *testing.T) {
+	assert := assert.New(t)
+
+	s, col := collection(t, "delivery")
+	defer s.Close()
+	delivered := &models.Delivery{
+		ID:          bson.NewObjectId(),
+		URL:         "https://hooks.example.com/vibe",
+		Event:       controllers.EventRegistered,
+		Status:      controllers.DeliveryDelivered,
+		Attempts:    1,
+		CreatedAt:   time.Now(),
+		DeliveredAt: time.Now(),
+	}
+	if err := col.Insert(delivered); err != nil {
+		t.Fatal(err)
+	}
+	defer col.RemoveId(delivered.ID)
+
+	admin := &controllers.User{Email: "test_admin@vibe.me", Username: "TEST_ADMIN_001", Password: "just_a_pass_123", Role: "admin"}
+	token := login(t, admin)
+	defer dropUser(admin.Username)
+
+	// Its payload is gone, replaying it would send an empty body.
+	rec := serve(echo.POST, "/webhooks/deliveries/"+delivered.ID.Hex()+"/replay", token, "")
+	assert.Equal(http.StatusNotFound, rec.Code, rec.Body.String())
+	_, err := controllers.ReplayDeliveries(delivered.ID)
+	assert.True(errors.Is(err, controllers.ErrNotFound))
+
+	d := models.Delivery{}
+	assert.Nil(col.FindId(delivered.ID).One(&d))
+	assert.Equal(controllers.DeliveryDelivered, d.Status)
+}
+
+func TestWebhookPayloadSealed(t *testing.T) {
+	assert := assert.New(t)
+
+	saved := models.Conf().Crypto
+	defer func() {
+		models.Conf().Crypto = saved
+		controllers.ReloadKeyring()
+	}()
+	models.Conf().Crypto.Primary = "k1"
+	models.Conf().Crypto.Keys = map[string]string{"k1": base64.StdEncoding.EncodeToString([]byte(strings.Repeat("1", 32)))}
+	models.Conf().Crypto.IndexKey = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("i", 32)))
+	controllers.ReloadKeyring()
+
+	received := make(chan []byte, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		select {
+		case received <- body:
+		default:
+		}
+	}))
+	defer receiver.Close()
+	hook := &models.Webhook{URL: receiver.URL, Secret: "test_webhook_secret", Events: []string{controllers.EventRegistered}}
+	assert.Nil(controllers.AddWebhook(hook))
+	defer controllers.RemoveWebhook(hook.ID)
+
+	s, col := collection(t, "delivery")
+	defer s.Close()
+	u := &controllers.User{}
+	u.ID, u.Username, u.GivenName, u.Phone = bson.NewObjectId(), "TEST_SEALED_001", "TEST_GIVEN", "+886912345678"
+	defer col.RemoveAll(bson.M{"user_id": u.ID})
+	controllers.EmitUserEvent(controllers.EventRegistered, u)
+
+	// Stored, the payload shows nothing of the account.
+	stored := bson.M{}
+	assert.Nil(col.Find(bson.M{"user_id": u.ID}).One(&stored))
+	assert.NotContains(stored["payload"], "TEST_GIVEN")
+	assert.NotContains(stored["payload"], "886912345678")
+
+	controllers.DispatchWebhooks()
+	select {
+	case body := <-received:
+		assert.Contains(string(body), "TEST_GIVEN")
+		assert.Contains(string(body), "886912345678")
+	case <-time.After(5 * time.Second):
+		t.Fatal("The delivery was not sent")
+	}
+}
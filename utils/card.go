@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Card validates payment card details before they are handed to a payment
+// gateway.
+type Card struct{}
+
+type cardBrand struct {
+	name    string
+	lengths []int
+	ranges  [][2]int // inclusive prefix ranges, compared on as many digits as the bound has
+}
+
+var cardBrands = []cardBrand{
+	{"amex", []int{15}, [][2]int{{34, 34}, {37, 37}}},
+	{"visa", []int{13, 16, 19}, [][2]int{{4, 4}}},
+	{"mastercard", []int{16}, [][2]int{{51, 55}, {2221, 2720}}},
+	{"discover", []int{16, 17, 18, 19}, [][2]int{{6011, 6011}, {644, 649}, {65, 65}}},
+	{"jcb", []int{16, 17, 18, 19}, [][2]int{{3528, 3589}}},
+	{"diners", []int{14, 15, 16, 17, 18, 19}, [][2]int{{300, 305}, {36, 36}, {38, 39}}},
+	{"unionpay", []int{16, 17, 18, 19}, [][2]int{{62, 62}}},
+}
+
+// Normalize strips the spaces and dashes people type in card numbers.
+func (c *Card) Normalize(number string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(number)
+}
+
+// Luhn reports whether a normalized number is all digits and passes the Luhn
+// checksum.
+func (c *Card) Luhn(number string) bool {
+	if len(number) < 12 || len(number) > 19 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(number); i++ {
+		d := int(number[len(number)-1-i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// Brand returns the card network of a normalized number, or an empty string
+// when the prefix and length match none.
+func (c *Card) Brand(number string) string {
+	for _, b := range cardBrands {
+		if !hasLength(b.lengths, len(number)) {
+			continue
+		}
+		for _, r := range b.ranges {
+			n := len(strconv.Itoa(r[0]))
+			if len(number) < n {
+				continue
+			}
+			prefix, err := strconv.Atoi(number[:n])
+			if err == nil && prefix >= r[0] && prefix <= r[1] {
+				return b.name
+			}
+		}
+	}
+	return ""
+}
+
+func hasLength(lengths []int, l int) bool {
+	for _, n := range lengths {
+		if n == l {
+			return true
+		}
+	}
+	return false
+}
+
+// Expiry parses an MMYY or MM/YY expiry date and refuses cards expired at
+// now. A card is valid through the last day of its expiry month.
+func (c *Card) Expiry(mmyy string, now time.Time) (time.Time, error) {
+	mmyy = strings.Replace(strings.TrimSpace(mmyy), "/", "", 1)
+	if len(mmyy) != 4 {
+		return time.Time{}, errors.New("BAD_EXPIRY")
+	}
+	month, err := strconv.Atoi(mmyy[:2])
+	if err != nil || month < 1 || month > 12 {
+		return time.Time{}, errors.New("BAD_EXPIRY")
+	}
+	year, err := strconv.Atoi(mmyy[2:])
+	if err != nil {
+		return time.Time{}, errors.New("BAD_EXPIRY")
+	}
+
+	end := time.Date(2000+year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC)
+	if !now.Before(end) {
+		return end, errors.New("CARD_EXPIRED")
+	}
+	return end, nil
+}
+
+// CidLength is the length of the security code printed on cards of brand.
+func (c *Card) CidLength(brand string) int {
+	if brand == "amex" {
+		return 4
+	}
+	return 3
+}
+
+// Mask hides every digit but the last four.
+func (c *Card) Mask(last4 string) string {
+	return "**** **** **** " + last4
+}
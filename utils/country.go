@@ -1,73 +1,217 @@
 package utils
 
+//go:generate go run gen_country.go
+
 import (
-	"encoding/json"
-	"io/ioutil"
-	"net/http"
-	"runtime"
-	"time"
+	"golang.org/x/text/unicode/norm"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Country looks up the ISO 3166-1 countries embedded in Vibe. Lookups by
+// code or exact name take constant time and need no network.
+type Country struct{}
+
+// CountryInfo is an ISO 3166-1 country. Names are keyed by language.
+type CountryInfo struct {
+	Alpha2  string            `json:"alpha2"`
+	Alpha3  string            `json:"alpha3"`
+	Numeric string            `json:"numeric"`
+	Calling string            `json:"calling_code"`
+	Names   map[string]string `json:"names"`
+	Aliases []string          `json:"-"`
+}
+
+var (
+	countryByCode = map[string]int{}
+	countryByName = map[string]int{}
+	// countryNames holds the folded names once each, for fuzzy matching.
+	countryNames []foldedName
 )
 
-// NOTE : struct doesn't work here due to the nature
-// of the JSON data from country.io
-// it has no field identifiers
+type foldedName struct {
+	name    []rune
+	country int
+}
+
+func init() {
+	for i, c := range countries {
+		countryByCode[c.Alpha2] = i
+		countryByCode[c.Alpha3] = i
+		countryByCode[c.Numeric] = i
+
+		names := append([]string{}, c.Aliases...)
+		for _, name := range c.Names {
+			names = append(names, name)
+		}
+		for _, name := range names {
+			folded := foldCountryName(name)
+			if _, ok := countryByName[folded]; ok {
+				continue
+			}
+			countryByName[folded] = i
+			countryNames = append(countryNames, foldedName{[]rune(folded), i})
+		}
+	}
+}
+
+// Lookup finds a country by its alpha-2, alpha-3 or numeric code, in any
+// case.
+func (c *Country) Lookup(code string) (CountryInfo, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if n, err := strconv.Atoi(code); err == nil && n > 0 && n < 1000 {
+		code = strconv.Itoa(1000 + n)[1:]
+	}
+	i, ok := countryByCode[code]
+	if !ok {
+		return CountryInfo{}, false
+	}
+	return countries[i], true
+}
 
-type Country struct {
-	Map map[string]interface{}
-	Log Logger
+// IsValid tells if code is an assigned alpha-2 code, in upper case as stored.
+func (c *Country) IsValid(code string) bool {
+	i, ok := countryByCode[code]
+	return ok && countries[i].Alpha2 == code
 }
 
-func (c *Country) Init() error {
-	_, filename, _, _ := runtime.Caller(1)
-	meta := map[string]interface{}{
-		"from":    filename,
-		"section": "GetCountry",
-		"time":    time.Now(),
+// Name returns the name of a country in lang, such as "fr" or "pt-BR", with
+// English as fallback. It is empty for an unknown code.
+func (c *Country) Name(code, lang string) string {
+	info, ok := c.Lookup(code)
+	if !ok {
+		return ""
+	}
+	lang = strings.ToLower(strings.SplitN(strings.Replace(lang, "_", "-", -1), "-", 2)[0])
+	if name, ok := info.Names[lang]; ok {
+		return name
 	}
+	return info.Names["en"]
+}
 
-	resp, err := http.Get("http://country.io/names.json")
-	if err != nil {
-		c.Log.Error(meta, "Failed to fetch from country.io")
-		return err
+// Match finds a country by name in any embedded language, or by a common
+// alias such as "UK". Case, accents and punctuation are ignored. When no name
+// is equal, the single closest name within a few typos is taken.
+func (c *Country) Match(name string) (CountryInfo, bool) {
+	folded := foldCountryName(name)
+	if folded == "" {
+		return CountryInfo{}, false
+	}
+	if i, ok := countryByName[folded]; ok {
+		return countries[i], true
 	}
-	defer resp.Body.Close()
 
-	jsonCountriesData, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		meta["section"] = "ReadRespBody"
-		c.Log.Error(meta, err.Error())
-		return err
+	query := []rune(folded)
+	// Short names are too close to one another to guess.
+	if len(query) < 4 {
+		return CountryInfo{}, false
+	}
+	limit := len(query) / 4
+	if limit > 3 {
+		limit = 3
 	}
 
-	//fmt.Println(string(jsonData))
-	// Decode JSON into our map
-	err = json.Unmarshal([]byte(jsonCountriesData), &c.Map)
-	if err != nil {
-		meta["section"] = "ParseFromJson"
-		c.Log.Error(meta, err.Error())
-		return err
+	best, found := limit+1, -1
+	for _, n := range countryNames {
+		d := editDistance(query, n.name, best+1)
+		switch {
+		case d < best:
+			best, found = d, n.country
+		case d == best && found != n.country:
+			found = -1 // as close to two countries, no guess
+		}
+	}
+	if found < 0 || best > limit {
+		return CountryInfo{}, false
 	}
+	return countries[found], true
+}
 
-	/*for iso2, name := range c.Map {
-		fmt.Println("ISO2 code:", iso2, "Country name :", name)
-	}*/
-	return nil
+// All returns every country, ordered by alpha-2 code.
+func (c *Country) All() []CountryInfo {
+	return append([]CountryInfo{}, countries...)
 }
 
+// Iso2Country returns the English name of an alpha-2 code, or an empty string.
 func (c *Country) Iso2Country(iso string) string {
-	if err := c.Init(); err == nil {
-		return c.Map[iso].(string)
+	if !c.IsValid(strings.ToUpper(iso)) {
+		return ""
 	}
-	return ""
+	return c.Name(iso, "en")
 }
 
+// Country2Iso returns the alpha-2 code of a country name, or an empty string.
 func (c *Country) Country2Iso(country string) string {
-	if err := c.Init(); err == nil {
-		for iso2, name := range c.Map {
-			if name == country {
-				return iso2
+	if info, ok := c.Match(country); ok {
+		return info.Alpha2
+	}
+	return ""
+}
+
+// foldCountryName reduces a name to lower case words without accents or
+// punctuation, so "Côte d’Ivoire" and "cote d'ivoire" meet.
+func foldCountryName(name string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case r == '&':
+			b.WriteString(" and ")
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(' ')
+		}
+	}
+
+	words := []string{}
+	for _, w := range strings.Fields(b.String()) {
+		switch w {
+		case "the":
+			continue
+		case "st":
+			w = "saint"
+		}
+		words = append(words, w)
+	}
+	return strings.Join(words, " ")
+}
+
+// editDistance is the Levenshtein distance of a and b, or max once it is
+// known to reach max.
+func editDistance(a, b []rune, max int) int {
+	if d := len(a) - len(b); d >= max || -d >= max {
+		return max
+	}
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+			if cur[j] < rowMin {
+				rowMin = cur[j]
 			}
 		}
+		if rowMin >= max {
+			return max
+		}
+		prev, cur = cur, prev
 	}
-	return ""
+	return prev[len(b)]
 }
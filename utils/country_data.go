@@ -0,0 +1,255 @@
+// Code generated by gen_country.go. DO NOT EDIT.
+
+package utils
+
+var countries = []CountryInfo{
+	{Alpha2: "AD", Alpha3: "AND", Numeric: "020", Calling: "376", Names: map[string]string{"de": "Andorra", "en": "Andorra", "es": "Andorra", "fr": "Andorre", "it": "Andorra", "ja": "アンドラ", "ko": "안도라", "pt": "Andorra", "ru": "Андорра", "zh": "安道尔"}},
+	{Alpha2: "AE", Alpha3: "ARE", Numeric: "784", Calling: "971", Names: map[string]string{"de": "Vereinigte Arabische Emirate", "en": "United Arab Emirates", "es": "Emiratos Árabes Unidos", "fr": "Émirats arabes unis", "it": "Emirati Arabi Uniti", "ja": "アラブ首長国連邦", "ko": "아랍에미리트", "pt": "Emirados Árabes Unidos", "ru": "ОАЭ", "zh": "阿拉伯联合酋长国"}},
+	{Alpha2: "AF", Alpha3: "AFG", Numeric: "004", Calling: "93", Names: map[string]string{"de": "Afghanistan", "en": "Afghanistan", "es": "Afganistán", "fr": "Afghanistan", "it": "Afghanistan", "ja": "アフガニスタン", "ko": "아프가니스탄", "pt": "Afeganistão", "ru": "Афганистан", "zh": "阿富汗"}},
+	{Alpha2: "AG", Alpha3: "ATG", Numeric: "028", Calling: "1", Names: map[string]string{"de": "Antigua und Barbuda", "en": "Antigua & Barbuda", "es": "Antigua y Barbuda", "fr": "Antigua-et-Barbuda", "it": "Antigua e Barbuda", "ja": "アンティグア・バーブーダ", "ko": "앤티가 바부다", "pt": "Antígua e Barbuda", "ru": "Антигуа и Барбуда", "zh": "安提瓜和巴布达"}},
+	{Alpha2: "AI", Alpha3: "AIA", Numeric: "660", Calling: "1", Names: map[string]string{"de": "Anguilla", "en": "Anguilla", "es": "Anguila", "fr": "Anguilla", "it": "Anguilla", "ja": "アンギラ", "ko": "앵귈라", "pt": "Anguilla", "ru": "Ангилья", "zh": "安圭拉"}},
+	{Alpha2: "AL", Alpha3: "ALB", Numeric: "008", Calling: "355", Names: map[string]string{"de": "Albanien", "en": "Albania", "es": "Albania", "fr": "Albanie", "it": "Albania", "ja": "アルバニア", "ko": "알바니아", "pt": "Albânia", "ru": "Албания", "zh": "阿尔巴尼亚"}},
+	{Alpha2: "AM", Alpha3: "ARM", Numeric: "051", Calling: "374", Names: map[string]string{"de": "Armenien", "en": "Armenia", "es": "Armenia", "fr": "Arménie", "it": "Armenia", "ja": "アルメニア", "ko": "아르메니아", "pt": "Armênia", "ru": "Армения", "zh": "亚美尼亚"}},
+	{Alpha2: "AO", Alpha3: "AGO", Numeric: "024", Calling: "244", Names: map[string]string{"de": "Angola", "en": "Angola", "es": "Angola", "fr": "Angola", "it": "Angola", "ja": "アンゴラ", "ko": "앙골라", "pt": "Angola", "ru": "Ангола", "zh": "安哥拉"}},
+	{Alpha2: "AQ", Alpha3: "ATA", Numeric: "010", Calling: "672", Names: map[string]string{"de": "Antarktis", "en": "Antarctica", "es": "Antártida", "fr": "Antarctique", "it": "Antartide", "ja": "南極", "ko": "남극 대륙", "pt": "Antártida", "ru": "Антарктида", "zh": "南极洲"}},
+	{Alpha2: "AR", Alpha3: "ARG", Numeric: "032", Calling: "54", Names: map[string]string{"de": "Argentinien", "en": "Argentina", "es": "Argentina", "fr": "Argentine", "it": "Argentina", "ja": "アルゼンチン", "ko": "아르헨티나", "pt": "Argentina", "ru": "Аргентина", "zh": "阿根廷"}},
+	{Alpha2: "AS", Alpha3: "ASM", Numeric: "016", Calling: "1", Names: map[string]string{"de": "Amerikanisch-Samoa", "en": "American Samoa", "es": "Samoa Americana", "fr": "Samoa américaines", "it": "Samoa americane", "ja": "米領サモア", "ko": "아메리칸 사모아", "pt": "Samoa Americana", "ru": "Американское Самоа", "zh": "美属萨摩亚"}},
+	{Alpha2: "AT", Alpha3: "AUT", Numeric: "040", Calling: "43", Names: map[string]string{"de": "Österreich", "en": "Austria", "es": "Austria", "fr": "Autriche", "it": "Austria", "ja": "オーストリア", "ko": "오스트리아", "pt": "Áustria", "ru": "Австрия", "zh": "奥地利"}},
+	{Alpha2: "AU", Alpha3: "AUS", Numeric: "036", Calling: "61", Names: map[string]string{"de": "Australien", "en": "Australia", "es": "Australia", "fr": "Australie", "it": "Australia", "ja": "オーストラリア", "ko": "오스트레일리아", "pt": "Austrália", "ru": "Австралия", "zh": "澳大利亚"}},
+	{Alpha2: "AW", Alpha3: "ABW", Numeric: "533", Calling: "297", Names: map[string]string{"de": "Aruba", "en": "Aruba", "es": "Aruba", "fr": "Aruba", "it": "Aruba", "ja": "アルバ", "ko": "아루바", "pt": "Aruba", "ru": "Аруба", "zh": "阿鲁巴"}},
+	{Alpha2: "AX", Alpha3: "ALA", Numeric: "248", Calling: "358", Names: map[string]string{"de": "Ålandinseln", "en": "Åland Islands", "es": "Islas Åland", "fr": "Îles Åland", "it": "Isole Åland", "ja": "オーランド諸島", "ko": "올란드 제도", "pt": "Ilhas Aland", "ru": "Аландские о-ва", "zh": "奥兰群岛"}},
+	{Alpha2: "AZ", Alpha3: "AZE", Numeric: "031", Calling: "994", Names: map[string]string{"de": "Aserbaidschan", "en": "Azerbaijan", "es": "Azerbaiyán", "fr": "Azerbaïdjan", "it": "Azerbaigian", "ja": "アゼルバイジャン", "ko": "아제르바이잔", "pt": "Azerbaijão", "ru": "Азербайджан", "zh": "阿塞拜疆"}},
+	{Alpha2: "BA", Alpha3: "BIH", Numeric: "070", Calling: "387", Names: map[string]string{"de": "Bosnien und Herzegowina", "en": "Bosnia & Herzegovina", "es": "Bosnia y Herzegovina", "fr": "Bosnie-Herzégovine", "it": "Bosnia ed Erzegovina", "ja": "ボスニア・ヘルツェゴビナ", "ko": "보스니아 헤르체고비나", "pt": "Bósnia e Herzegovina", "ru": "Босния и Герцеговина", "zh": "波斯尼亚和黑塞哥维那"}, Aliases: []string{"Bosnia", "Bosnia and Herzegovina"}},
+	{Alpha2: "BB", Alpha3: "BRB", Numeric: "052", Calling: "1", Names: map[string]string{"de": "Barbados", "en": "Barbados", "es": "Barbados", "fr": "Barbade", "it": "Barbados", "ja": "バルバドス", "ko": "바베이도스", "pt": "Barbados", "ru": "Барбадос", "zh": "巴巴多斯"}},
+	{Alpha2: "BD", Alpha3: "BGD", Numeric: "050", Calling: "880", Names: map[string]string{"de": "Bangladesch", "en": "Bangladesh", "es": "Bangladés", "fr": "Bangladesh", "it": "Bangladesh", "ja": "バングラデシュ", "ko": "방글라데시", "pt": "Bangladesh", "ru": "Бангладеш", "zh": "孟加拉国"}},
+	{Alpha2: "BE", Alpha3: "BEL", Numeric: "056", Calling: "32", Names: map[string]string{"de": "Belgien", "en": "Belgium", "es": "Bélgica", "fr": "Belgique", "it": "Belgio", "ja": "ベルギー", "ko": "벨기에", "pt": "Bélgica", "ru": "Бельгия", "zh": "比利时"}},
+	{Alpha2: "BF", Alpha3: "BFA", Numeric: "854", Calling: "226", Names: map[string]string{"de": "Burkina Faso", "en": "Burkina Faso", "es": "Burkina Faso", "fr": "Burkina Faso", "it": "Burkina Faso", "ja": "ブルキナファソ", "ko": "부르키나파소", "pt": "Burquina Faso", "ru": "Буркина-Фасо", "zh": "布基纳法索"}},
+	{Alpha2: "BG", Alpha3: "BGR", Numeric: "100", Calling: "359", Names: map[string]string{"de": "Bulgarien", "en": "Bulgaria", "es": "Bulgaria", "fr": "Bulgarie", "it": "Bulgaria", "ja": "ブルガリア", "ko": "불가리아", "pt": "Bulgária", "ru": "Болгария", "zh": "保加利亚"}},
+	{Alpha2: "BH", Alpha3: "BHR", Numeric: "048", Calling: "973", Names: map[string]string{"de": "Bahrain", "en": "Bahrain", "es": "Baréin", "fr": "Bahreïn", "it": "Bahrein", "ja": "バーレーン", "ko": "바레인", "pt": "Bahrein", "ru": "Бахрейн", "zh": "巴林"}},
+	{Alpha2: "BI", Alpha3: "BDI", Numeric: "108", Calling: "257", Names: map[string]string{"de": "Burundi", "en": "Burundi", "es": "Burundi", "fr": "Burundi", "it": "Burundi", "ja": "ブルンジ", "ko": "부룬디", "pt": "Burundi", "ru": "Бурунди", "zh": "布隆迪"}},
+	{Alpha2: "BJ", Alpha3: "BEN", Numeric: "204", Calling: "229", Names: map[string]string{"de": "Benin", "en": "Benin", "es": "Benín", "fr": "Bénin", "it": "Benin", "ja": "ベナン", "ko": "베냉", "pt": "Benin", "ru": "Бенин", "zh": "贝宁"}},
+	{Alpha2: "BL", Alpha3: "BLM", Numeric: "652", Calling: "590", Names: map[string]string{"de": "St. Barthélemy", "en": "St. Barthélemy", "es": "San Bartolomé", "fr": "Saint-Barthélemy", "it": "Saint-Barthélemy", "ja": "サン・バルテルミー", "ko": "생바르텔레미", "pt": "São Bartolomeu", "ru": "Сен-Бартелеми", "zh": "圣巴泰勒米"}},
+	{Alpha2: "BM", Alpha3: "BMU", Numeric: "060", Calling: "1", Names: map[string]string{"de": "Bermuda", "en": "Bermuda", "es": "Bermudas", "fr": "Bermudes", "it": "Bermuda", "ja": "バミューダ", "ko": "버뮤다", "pt": "Bermudas", "ru": "Бермудские о-ва", "zh": "百慕大"}},
+	{Alpha2: "BN", Alpha3: "BRN", Numeric: "096", Calling: "673", Names: map[string]string{"de": "Brunei Darussalam", "en": "Brunei", "es": "Brunéi", "fr": "Brunéi Darussalam", "it": "Brunei", "ja": "ブルネイ", "ko": "브루나이", "pt": "Brunei", "ru": "Бруней-Даруссалам", "zh": "文莱"}},
+	{Alpha2: "BO", Alpha3: "BOL", Numeric: "068", Calling: "591", Names: map[string]string{"de": "Bolivien", "en": "Bolivia", "es": "Bolivia", "fr": "Bolivie", "it": "Bolivia", "ja": "ボリビア", "ko": "볼리비아", "pt": "Bolívia", "ru": "Боливия", "zh": "玻利维亚"}, Aliases: []string{"Plurinational State of Bolivia"}},
+	{Alpha2: "BQ", Alpha3: "BES", Numeric: "535", Calling: "599", Names: map[string]string{"de": "Bonaire, Sint Eustatius und Saba", "en": "Caribbean Netherlands", "es": "Caribe neerlandés", "fr": "Pays-Bas caribéens", "it": "Caraibi olandesi", "ja": "オランダ領カリブ", "ko": "네덜란드령 카리브", "pt": "Países Baixos Caribenhos", "ru": "Бонэйр, Синт-Эстатиус и Саба", "zh": "荷属加勒比区"}},
+	{Alpha2: "BR", Alpha3: "BRA", Numeric: "076", Calling: "55", Names: map[string]string{"de": "Brasilien", "en": "Brazil", "es": "Brasil", "fr": "Brésil", "it": "Brasile", "ja": "ブラジル", "ko": "브라질", "pt": "Brasil", "ru": "Бразилия", "zh": "巴西"}},
+	{Alpha2: "BS", Alpha3: "BHS", Numeric: "044", Calling: "1", Names: map[string]string{"de": "Bahamas", "en": "Bahamas", "es": "Bahamas", "fr": "Bahamas", "it": "Bahamas", "ja": "バハマ", "ko": "바하마", "pt": "Bahamas", "ru": "Багамы", "zh": "巴哈马"}},
+	{Alpha2: "BT", Alpha3: "BTN", Numeric: "064", Calling: "975", Names: map[string]string{"de": "Bhutan", "en": "Bhutan", "es": "Bután", "fr": "Bhoutan", "it": "Bhutan", "ja": "ブータン", "ko": "부탄", "pt": "Butão", "ru": "Бутан", "zh": "不丹"}},
+	{Alpha2: "BV", Alpha3: "BVT", Numeric: "074", Calling: "47", Names: map[string]string{"de": "Bouvetinsel", "en": "Bouvet Island", "es": "Isla Bouvet", "fr": "Île Bouvet", "it": "Isola Bouvet", "ja": "ブーベ島", "ko": "부베섬", "pt": "Ilha Bouvet", "ru": "о-в Буве", "zh": "布韦岛"}},
+	{Alpha2: "BW", Alpha3: "BWA", Numeric: "072", Calling: "267", Names: map[string]string{"de": "Botsuana", "en": "Botswana", "es": "Botsuana", "fr": "Botswana", "it": "Botswana", "ja": "ボツワナ", "ko": "보츠와나", "pt": "Botsuana", "ru": "Ботсвана", "zh": "博茨瓦纳"}},
+	{Alpha2: "BY", Alpha3: "BLR", Numeric: "112", Calling: "375", Names: map[string]string{"de": "Belarus", "en": "Belarus", "es": "Bielorrusia", "fr": "Biélorussie", "it": "Bielorussia", "ja": "ベラルーシ", "ko": "벨라루스", "pt": "Bielorrússia", "ru": "Беларусь", "zh": "白俄罗斯"}},
+	{Alpha2: "BZ", Alpha3: "BLZ", Numeric: "084", Calling: "501", Names: map[string]string{"de": "Belize", "en": "Belize", "es": "Belice", "fr": "Belize", "it": "Belize", "ja": "ベリーズ", "ko": "벨리즈", "pt": "Belize", "ru": "Белиз", "zh": "伯利兹"}},
+	{Alpha2: "CA", Alpha3: "CAN", Numeric: "124", Calling: "1", Names: map[string]string{"de": "Kanada", "en": "Canada", "es": "Canadá", "fr": "Canada", "it": "Canada", "ja": "カナダ", "ko": "캐나다", "pt": "Canadá", "ru": "Канада", "zh": "加拿大"}},
+	{Alpha2: "CC", Alpha3: "CCK", Numeric: "166", Calling: "61", Names: map[string]string{"de": "Kokosinseln", "en": "Cocos (Keeling) Islands", "es": "Islas Cocos", "fr": "Îles Cocos", "it": "Isole Cocos (Keeling)", "ja": "ココス(キーリング)諸島", "ko": "코코스 제도", "pt": "Ilhas Cocos (Keeling)", "ru": "Кокосовые о-ва", "zh": "科科斯（基林）群岛"}},
+	{Alpha2: "CD", Alpha3: "COD", Numeric: "180", Calling: "243", Names: map[string]string{"de": "Kongo-Kinshasa", "en": "Congo - Kinshasa", "es": "República Democrática del Congo", "fr": "Congo-Kinshasa", "it": "Congo - Kinshasa", "ja": "コンゴ民主共和国(キンシャサ)", "ko": "콩고-킨샤사", "pt": "Congo - Kinshasa", "ru": "Конго - Киншаса", "zh": "刚果（金）"}, Aliases: []string{"DR Congo", "DRC", "Democratic Republic of the Congo"}},
+	{Alpha2: "CF", Alpha3: "CAF", Numeric: "140", Calling: "236", Names: map[string]string{"de": "Zentralafrikanische Republik", "en": "Central African Republic", "es": "República Centroafricana", "fr": "République centrafricaine", "it": "Repubblica Centrafricana", "ja": "中央アフリカ共和国", "ko": "중앙 아프리카 공화국", "pt": "República Centro-Africana", "ru": "Центрально-Африканская Республика", "zh": "中非共和国"}},
+	{Alpha2: "CG", Alpha3: "COG", Numeric: "178", Calling: "242", Names: map[string]string{"de": "Kongo-Brazzaville", "en": "Congo - Brazzaville", "es": "República del Congo", "fr": "Congo-Brazzaville", "it": "Congo-Brazzaville", "ja": "コンゴ共和国(ブラザビル)", "ko": "콩고-브라자빌", "pt": "Congo - Brazzaville", "ru": "Конго - Браззавиль", "zh": "刚果（布）"}, Aliases: []string{"Republic of the Congo"}},
+	{Alpha2: "CH", Alpha3: "CHE", Numeric: "756", Calling: "41", Names: map[string]string{"de": "Schweiz", "en": "Switzerland", "es": "Suiza", "fr": "Suisse", "it": "Svizzera", "ja": "スイス", "ko": "스위스", "pt": "Suíça", "ru": "Швейцария", "zh": "瑞士"}},
+	{Alpha2: "CI", Alpha3: "CIV", Numeric: "384", Calling: "225", Names: map[string]string{"de": "Côte d’Ivoire", "en": "Côte d’Ivoire", "es": "Côte d’Ivoire", "fr": "Côte d’Ivoire", "it": "Costa d’Avorio", "ja": "コートジボワール", "ko": "코트디부아르", "pt": "Costa do Marfim", "ru": "Кот-д’Ивуар", "zh": "科特迪瓦"}, Aliases: []string{"Ivory Coast"}},
+	{Alpha2: "CK", Alpha3: "COK", Numeric: "184", Calling: "682", Names: map[string]string{"de": "Cookinseln", "en": "Cook Islands", "es": "Islas Cook", "fr": "Îles Cook", "it": "Isole Cook", "ja": "クック諸島", "ko": "쿡 제도", "pt": "Ilhas Cook", "ru": "Острова Кука", "zh": "库克群岛"}},
+	{Alpha2: "CL", Alpha3: "CHL", Numeric: "152", Calling: "56", Names: map[string]string{"de": "Chile", "en": "Chile", "es": "Chile", "fr": "Chili", "it": "Cile", "ja": "チリ", "ko": "칠레", "pt": "Chile", "ru": "Чили", "zh": "智利"}},
+	{Alpha2: "CM", Alpha3: "CMR", Numeric: "120", Calling: "237", Names: map[string]string{"de": "Kamerun", "en": "Cameroon", "es": "Camerún", "fr": "Cameroun", "it": "Camerun", "ja": "カメルーン", "ko": "카메룬", "pt": "Camarões", "ru": "Камерун", "zh": "喀麦隆"}},
+	{Alpha2: "CN", Alpha3: "CHN", Numeric: "156", Calling: "86", Names: map[string]string{"de": "China", "en": "China", "es": "China", "fr": "Chine", "it": "Cina", "ja": "中国", "ko": "중국", "pt": "China", "ru": "Китай", "zh": "中国"}},
+	{Alpha2: "CO", Alpha3: "COL", Numeric: "170", Calling: "57", Names: map[string]string{"de": "Kolumbien", "en": "Colombia", "es": "Colombia", "fr": "Colombie", "it": "Colombia", "ja": "コロンビア", "ko": "콜롬비아", "pt": "Colômbia", "ru": "Колумбия", "zh": "哥伦比亚"}},
+	{Alpha2: "CR", Alpha3: "CRI", Numeric: "188", Calling: "506", Names: map[string]string{"de": "Costa Rica", "en": "Costa Rica", "es": "Costa Rica", "fr": "Costa Rica", "it": "Costa Rica", "ja": "コスタリカ", "ko": "코스타리카", "pt": "Costa Rica", "ru": "Коста-Рика", "zh": "哥斯达黎加"}},
+	{Alpha2: "CU", Alpha3: "CUB", Numeric: "192", Calling: "53", Names: map[string]string{"de": "Kuba", "en": "Cuba", "es": "Cuba", "fr": "Cuba", "it": "Cuba", "ja": "キューバ", "ko": "쿠바", "pt": "Cuba", "ru": "Куба", "zh": "古巴"}},
+	{Alpha2: "CV", Alpha3: "CPV", Numeric: "132", Calling: "238", Names: map[string]string{"de": "Cabo Verde", "en": "Cape Verde", "es": "Cabo Verde", "fr": "Cap-Vert", "it": "Capo Verde", "ja": "カーボベルデ", "ko": "카보베르데", "pt": "Cabo Verde", "ru": "Кабо-Верде", "zh": "佛得角"}, Aliases: []string{"Cabo Verde"}},
+	{Alpha2: "CW", Alpha3: "CUW", Numeric: "531", Calling: "599", Names: map[string]string{"de": "Curaçao", "en": "Curaçao", "es": "Curazao", "fr": "Curaçao", "it": "Curaçao", "ja": "キュラソー", "ko": "퀴라소", "pt": "Curaçao", "ru": "Кюрасао", "zh": "库拉索"}},
+	{Alpha2: "CX", Alpha3: "CXR", Numeric: "162", Calling: "61", Names: map[string]string{"de": "Weihnachtsinsel", "en": "Christmas Island", "es": "Isla de Navidad", "fr": "Île Christmas", "it": "Isola Christmas", "ja": "クリスマス島", "ko": "크리스마스섬", "pt": "Ilha Christmas", "ru": "о-в Рождества", "zh": "圣诞岛"}},
+	{Alpha2: "CY", Alpha3: "CYP", Numeric: "196", Calling: "357", Names: map[string]string{"de": "Zypern", "en": "Cyprus", "es": "Chipre", "fr": "Chypre", "it": "Cipro", "ja": "キプロス", "ko": "키프로스", "pt": "Chipre", "ru": "Кипр", "zh": "塞浦路斯"}},
+	{Alpha2: "CZ", Alpha3: "CZE", Numeric: "203", Calling: "420", Names: map[string]string{"de": "Tschechien", "en": "Czechia", "es": "Chequia", "fr": "Tchéquie", "it": "Cechia", "ja": "チェコ", "ko": "체코", "pt": "Tchéquia", "ru": "Чехия", "zh": "捷克"}, Aliases: []string{"Czech Republic"}},
+	{Alpha2: "DE", Alpha3: "DEU", Numeric: "276", Calling: "49", Names: map[string]string{"de": "Deutschland", "en": "Germany", "es": "Alemania", "fr": "Allemagne", "it": "Germania", "ja": "ドイツ", "ko": "독일", "pt": "Alemanha", "ru": "Германия", "zh": "德国"}},
+	{Alpha2: "DJ", Alpha3: "DJI", Numeric: "262", Calling: "253", Names: map[string]string{"de": "Dschibuti", "en": "Djibouti", "es": "Yibuti", "fr": "Djibouti", "it": "Gibuti", "ja": "ジブチ", "ko": "지부티", "pt": "Djibuti", "ru": "Джибути", "zh": "吉布提"}},
+	{Alpha2: "DK", Alpha3: "DNK", Numeric: "208", Calling: "45", Names: map[string]string{"de": "Dänemark", "en": "Denmark", "es": "Dinamarca", "fr": "Danemark", "it": "Danimarca", "ja": "デンマーク", "ko": "덴마크", "pt": "Dinamarca", "ru": "Дания", "zh": "丹麦"}},
+	{Alpha2: "DM", Alpha3: "DMA", Numeric: "212", Calling: "1", Names: map[string]string{"de": "Dominica", "en": "Dominica", "es": "Dominica", "fr": "Dominique", "it": "Dominica", "ja": "ドミニカ国", "ko": "도미니카", "pt": "Dominica", "ru": "Доминика", "zh": "多米尼克"}},
+	{Alpha2: "DO", Alpha3: "DOM", Numeric: "214", Calling: "1", Names: map[string]string{"de": "Dominikanische Republik", "en": "Dominican Republic", "es": "República Dominicana", "fr": "République dominicaine", "it": "Repubblica Dominicana", "ja": "ドミニカ共和国", "ko": "도미니카 공화국", "pt": "República Dominicana", "ru": "Доминиканская Республика", "zh": "多米尼加共和国"}},
+	{Alpha2: "DZ", Alpha3: "DZA", Numeric: "012", Calling: "213", Names: map[string]string{"de": "Algerien", "en": "Algeria", "es": "Argelia", "fr": "Algérie", "it": "Algeria", "ja": "アルジェリア", "ko": "알제리", "pt": "Argélia", "ru": "Алжир", "zh": "阿尔及利亚"}},
+	{Alpha2: "EC", Alpha3: "ECU", Numeric: "218", Calling: "593", Names: map[string]string{"de": "Ecuador", "en": "Ecuador", "es": "Ecuador", "fr": "Équateur", "it": "Ecuador", "ja": "エクアドル", "ko": "에콰도르", "pt": "Equador", "ru": "Эквадор", "zh": "厄瓜多尔"}},
+	{Alpha2: "EE", Alpha3: "EST", Numeric: "233", Calling: "372", Names: map[string]string{"de": "Estland", "en": "Estonia", "es": "Estonia", "fr": "Estonie", "it": "Estonia", "ja": "エストニア", "ko": "에스토니아", "pt": "Estônia", "ru": "Эстония", "zh": "爱沙尼亚"}},
+	{Alpha2: "EG", Alpha3: "EGY", Numeric: "818", Calling: "20", Names: map[string]string{"de": "Ägypten", "en": "Egypt", "es": "Egipto", "fr": "Égypte", "it": "Egitto", "ja": "エジプト", "ko": "이집트", "pt": "Egito", "ru": "Египет", "zh": "埃及"}},
+	{Alpha2: "EH", Alpha3: "ESH", Numeric: "732", Calling: "212", Names: map[string]string{"de": "Westsahara", "en": "Western Sahara", "es": "Sáhara Occidental", "fr": "Sahara occidental", "it": "Sahara occidentale", "ja": "西サハラ", "ko": "서사하라", "pt": "Saara Ocidental", "ru": "Западная Сахара", "zh": "西撒哈拉"}},
+	{Alpha2: "ER", Alpha3: "ERI", Numeric: "232", Calling: "291", Names: map[string]string{"de": "Eritrea", "en": "Eritrea", "es": "Eritrea", "fr": "Érythrée", "it": "Eritrea", "ja": "エリトリア", "ko": "에리트리아", "pt": "Eritreia", "ru": "Эритрея", "zh": "厄立特里亚"}},
+	{Alpha2: "ES", Alpha3: "ESP", Numeric: "724", Calling: "34", Names: map[string]string{"de": "Spanien", "en": "Spain", "es": "España", "fr": "Espagne", "it": "Spagna", "ja": "スペイン", "ko": "스페인", "pt": "Espanha", "ru": "Испания", "zh": "西班牙"}},
+	{Alpha2: "ET", Alpha3: "ETH", Numeric: "231", Calling: "251", Names: map[string]string{"de": "Äthiopien", "en": "Ethiopia", "es": "Etiopía", "fr": "Éthiopie", "it": "Etiopia", "ja": "エチオピア", "ko": "에티오피아", "pt": "Etiópia", "ru": "Эфиопия", "zh": "埃塞俄比亚"}},
+	{Alpha2: "FI", Alpha3: "FIN", Numeric: "246", Calling: "358", Names: map[string]string{"de": "Finnland", "en": "Finland", "es": "Finlandia", "fr": "Finlande", "it": "Finlandia", "ja": "フィンランド", "ko": "핀란드", "pt": "Finlândia", "ru": "Финляндия", "zh": "芬兰"}},
+	{Alpha2: "FJ", Alpha3: "FJI", Numeric: "242", Calling: "679", Names: map[string]string{"de": "Fidschi", "en": "Fiji", "es": "Fiyi", "fr": "Fidji", "it": "Figi", "ja": "フィジー", "ko": "피지", "pt": "Fiji", "ru": "Фиджи", "zh": "斐济"}},
+	{Alpha2: "FK", Alpha3: "FLK", Numeric: "238", Calling: "500", Names: map[string]string{"de": "Falklandinseln", "en": "Falkland Islands", "es": "Islas Malvinas", "fr": "Îles Malouines", "it": "Isole Falkland", "ja": "フォークランド諸島", "ko": "포클랜드 제도", "pt": "Ilhas Malvinas", "ru": "Фолклендские о-ва", "zh": "福克兰群岛"}},
+	{Alpha2: "FM", Alpha3: "FSM", Numeric: "583", Calling: "691", Names: map[string]string{"de": "Mikronesien", "en": "Micronesia", "es": "Micronesia", "fr": "États fédérés de Micronésie", "it": "Micronesia", "ja": "ミクロネシア連邦", "ko": "미크로네시아", "pt": "Micronésia", "ru": "Федеративные Штаты Микронезии", "zh": "密克罗尼西亚"}},
+	{Alpha2: "FO", Alpha3: "FRO", Numeric: "234", Calling: "298", Names: map[string]string{"de": "Färöer", "en": "Faroe Islands", "es": "Islas Feroe", "fr": "Îles Féroé", "it": "Isole Fær Øer", "ja": "フェロー諸島", "ko": "페로 제도", "pt": "Ilhas Faroe", "ru": "Фарерские о-ва", "zh": "法罗群岛"}},
+	{Alpha2: "FR", Alpha3: "FRA", Numeric: "250", Calling: "33", Names: map[string]string{"de": "Frankreich", "en": "France", "es": "Francia", "fr": "France", "it": "Francia", "ja": "フランス", "ko": "프랑스", "pt": "França", "ru": "Франция", "zh": "法国"}},
+	{Alpha2: "GA", Alpha3: "GAB", Numeric: "266", Calling: "241", Names: map[string]string{"de": "Gabun", "en": "Gabon", "es": "Gabón", "fr": "Gabon", "it": "Gabon", "ja": "ガボン", "ko": "가봉", "pt": "Gabão", "ru": "Габон", "zh": "加蓬"}},
+	{Alpha2: "GB", Alpha3: "GBR", Numeric: "826", Calling: "44", Names: map[string]string{"de": "Vereinigtes Königreich", "en": "United Kingdom", "es": "Reino Unido", "fr": "Royaume-Uni", "it": "Regno Unito", "ja": "イギリス", "ko": "영국", "pt": "Reino Unido", "ru": "Великобритания", "zh": "英国"}, Aliases: []string{"UK", "Great Britain", "Britain", "England", "Scotland", "Wales", "Northern Ireland"}},
+	{Alpha2: "GD", Alpha3: "GRD", Numeric: "308", Calling: "1", Names: map[string]string{"de": "Grenada", "en": "Grenada", "es": "Granada", "fr": "Grenade", "it": "Grenada", "ja": "グレナダ", "ko": "그레나다", "pt": "Granada", "ru": "Гренада", "zh": "格林纳达"}},
+	{Alpha2: "GE", Alpha3: "GEO", Numeric: "268", Calling: "995", Names: map[string]string{"de": "Georgien", "en": "Georgia", "es": "Georgia", "fr": "Géorgie", "it": "Georgia", "ja": "ジョージア", "ko": "조지아", "pt": "Geórgia", "ru": "Грузия", "zh": "格鲁吉亚"}},
+	{Alpha2: "GF", Alpha3: "GUF", Numeric: "254", Calling: "594", Names: map[string]string{"de": "Französisch-Guayana", "en": "French Guiana", "es": "Guayana Francesa", "fr": "Guyane française", "it": "Guyana francese", "ja": "仏領ギアナ", "ko": "프랑스령 기아나", "pt": "Guiana Francesa", "ru": "Французская Гвиана", "zh": "法属圭亚那"}},
+	{Alpha2: "GG", Alpha3: "GGY", Numeric: "831", Calling: "44", Names: map[string]string{"de": "Guernsey", "en": "Guernsey", "es": "Guernsey", "fr": "Guernesey", "it": "Guernsey", "ja": "ガーンジー", "ko": "건지", "pt": "Guernsey", "ru": "Гернси", "zh": "根西岛"}},
+	{Alpha2: "GH", Alpha3: "GHA", Numeric: "288", Calling: "233", Names: map[string]string{"de": "Ghana", "en": "Ghana", "es": "Ghana", "fr": "Ghana", "it": "Ghana", "ja": "ガーナ", "ko": "가나", "pt": "Gana", "ru": "Гана", "zh": "加纳"}},
+	{Alpha2: "GI", Alpha3: "GIB", Numeric: "292", Calling: "350", Names: map[string]string{"de": "Gibraltar", "en": "Gibraltar", "es": "Gibraltar", "fr": "Gibraltar", "it": "Gibilterra", "ja": "ジブラルタル", "ko": "지브롤터", "pt": "Gibraltar", "ru": "Гибралтар", "zh": "直布罗陀"}},
+	{Alpha2: "GL", Alpha3: "GRL", Numeric: "304", Calling: "299", Names: map[string]string{"de": "Grönland", "en": "Greenland", "es": "Groenlandia", "fr": "Groenland", "it": "Groenlandia", "ja": "グリーンランド", "ko": "그린란드", "pt": "Groenlândia", "ru": "Гренландия", "zh": "格陵兰"}},
+	{Alpha2: "GM", Alpha3: "GMB", Numeric: "270", Calling: "220", Names: map[string]string{"de": "Gambia", "en": "Gambia", "es": "Gambia", "fr": "Gambie", "it": "Gambia", "ja": "ガンビア", "ko": "감비아", "pt": "Gâmbia", "ru": "Гамбия", "zh": "冈比亚"}},
+	{Alpha2: "GN", Alpha3: "GIN", Numeric: "324", Calling: "224", Names: map[string]string{"de": "Guinea", "en": "Guinea", "es": "Guinea", "fr": "Guinée", "it": "Guinea", "ja": "ギニア", "ko": "기니", "pt": "Guiné", "ru": "Гвинея", "zh": "几内亚"}},
+	{Alpha2: "GP", Alpha3: "GLP", Numeric: "312", Calling: "590", Names: map[string]string{"de": "Guadeloupe", "en": "Guadeloupe", "es": "Guadalupe", "fr": "Guadeloupe", "it": "Guadalupa", "ja": "グアドループ", "ko": "과들루프", "pt": "Guadalupe", "ru": "Гваделупа", "zh": "瓜德罗普"}},
+	{Alpha2: "GQ", Alpha3: "GNQ", Numeric: "226", Calling: "240", Names: map[string]string{"de": "Äquatorialguinea", "en": "Equatorial Guinea", "es": "Guinea Ecuatorial", "fr": "Guinée équatoriale", "it": "Guinea Equatoriale", "ja": "赤道ギニア", "ko": "적도 기니", "pt": "Guiné Equatorial", "ru": "Экваториальная Гвинея", "zh": "赤道几内亚"}},
+	{Alpha2: "GR", Alpha3: "GRC", Numeric: "300", Calling: "30", Names: map[string]string{"de": "Griechenland", "en": "Greece", "es": "Grecia", "fr": "Grèce", "it": "Grecia", "ja": "ギリシャ", "ko": "그리스", "pt": "Grécia", "ru": "Греция", "zh": "希腊"}},
+	{Alpha2: "GS", Alpha3: "SGS", Numeric: "239", Calling: "500", Names: map[string]string{"de": "Südgeorgien und die Südlichen Sandwichinseln", "en": "South Georgia & South Sandwich Islands", "es": "Islas Georgia del Sur y Sandwich del Sur", "fr": "Géorgie du Sud et îles Sandwich du Sud", "it": "Georgia del Sud e Sandwich australi", "ja": "サウスジョージア・サウスサンドウィッチ諸島", "ko": "사우스조지아 사우스샌드위치 제도", "pt": "Ilhas Geórgia do Sul e Sandwich do Sul", "ru": "Южная Георгия и Южные Сандвичевы о-ва", "zh": "南乔治亚和南桑威奇群岛"}},
+	{Alpha2: "GT", Alpha3: "GTM", Numeric: "320", Calling: "502", Names: map[string]string{"de": "Guatemala", "en": "Guatemala", "es": "Guatemala", "fr": "Guatemala", "it": "Guatemala", "ja": "グアテマラ", "ko": "과테말라", "pt": "Guatemala", "ru": "Гватемала", "zh": "危地马拉"}},
+	{Alpha2: "GU", Alpha3: "GUM", Numeric: "316", Calling: "1", Names: map[string]string{"de": "Guam", "en": "Guam", "es": "Guam", "fr": "Guam", "it": "Guam", "ja": "グアム", "ko": "괌", "pt": "Guam", "ru": "Гуам", "zh": "关岛"}},
+	{Alpha2: "GW", Alpha3: "GNB", Numeric: "624", Calling: "245", Names: map[string]string{"de": "Guinea-Bissau", "en": "Guinea-Bissau", "es": "Guinea-Bisáu", "fr": "Guinée-Bissau", "it": "Guinea-Bissau", "ja": "ギニアビサウ", "ko": "기니비사우", "pt": "Guiné-Bissau", "ru": "Гвинея-Бисау", "zh": "几内亚比绍"}},
+	{Alpha2: "GY", Alpha3: "GUY", Numeric: "328", Calling: "592", Names: map[string]string{"de": "Guyana", "en": "Guyana", "es": "Guyana", "fr": "Guyana", "it": "Guyana", "ja": "ガイアナ", "ko": "가이아나", "pt": "Guiana", "ru": "Гайана", "zh": "圭亚那"}},
+	{Alpha2: "HK", Alpha3: "HKG", Numeric: "344", Calling: "852", Names: map[string]string{"de": "Sonderverwaltungsregion Hongkong", "en": "Hong Kong SAR China", "es": "RAE de Hong Kong (China)", "fr": "R.A.S. chinoise de Hong Kong", "it": "RAS di Hong Kong", "ja": "中華人民共和国香港特別行政区", "ko": "홍콩(중국 특별행정구)", "pt": "Hong Kong, RAE da China", "ru": "Гонконг (САР)", "zh": "中国香港特别行政区"}, Aliases: []string{"Hong Kong"}},
+	{Alpha2: "HM", Alpha3: "HMD", Numeric: "334", Calling: "672", Names: map[string]string{"de": "Heard und McDonaldinseln", "en": "Heard & McDonald Islands", "es": "Islas Heard y McDonald", "fr": "Îles Heard et McDonald", "it": "Isole Heard e McDonald", "ja": "ハード島・マクドナルド諸島", "ko": "허드 맥도널드 제도", "pt": "Ilhas Heard e McDonald", "ru": "о-ва Херд и Макдональд", "zh": "赫德岛和麦克唐纳群岛"}},
+	{Alpha2: "HN", Alpha3: "HND", Numeric: "340", Calling: "504", Names: map[string]string{"de": "Honduras", "en": "Honduras", "es": "Honduras", "fr": "Honduras", "it": "Honduras", "ja": "ホンジュラス", "ko": "온두라스", "pt": "Honduras", "ru": "Гондурас", "zh": "洪都拉斯"}},
+	{Alpha2: "HR", Alpha3: "HRV", Numeric: "191", Calling: "385", Names: map[string]string{"de": "Kroatien", "en": "Croatia", "es": "Croacia", "fr": "Croatie", "it": "Croazia", "ja": "クロアチア", "ko": "크로아티아", "pt": "Croácia", "ru": "Хорватия", "zh": "克罗地亚"}},
+	{Alpha2: "HT", Alpha3: "HTI", Numeric: "332", Calling: "509", Names: map[string]string{"de": "Haiti", "en": "Haiti", "es": "Haití", "fr": "Haïti", "it": "Haiti", "ja": "ハイチ", "ko": "아이티", "pt": "Haiti", "ru": "Гаити", "zh": "海地"}},
+	{Alpha2: "HU", Alpha3: "HUN", Numeric: "348", Calling: "36", Names: map[string]string{"de": "Ungarn", "en": "Hungary", "es": "Hungría", "fr": "Hongrie", "it": "Ungheria", "ja": "ハンガリー", "ko": "헝가리", "pt": "Hungria", "ru": "Венгрия", "zh": "匈牙利"}},
+	{Alpha2: "ID", Alpha3: "IDN", Numeric: "360", Calling: "62", Names: map[string]string{"de": "Indonesien", "en": "Indonesia", "es": "Indonesia", "fr": "Indonésie", "it": "Indonesia", "ja": "インドネシア", "ko": "인도네시아", "pt": "Indonésia", "ru": "Индонезия", "zh": "印度尼西亚"}},
+	{Alpha2: "IE", Alpha3: "IRL", Numeric: "372", Calling: "353", Names: map[string]string{"de": "Irland", "en": "Ireland", "es": "Irlanda", "fr": "Irlande", "it": "Irlanda", "ja": "アイルランド", "ko": "아일랜드", "pt": "Irlanda", "ru": "Ирландия", "zh": "爱尔兰"}},
+	{Alpha2: "IL", Alpha3: "ISR", Numeric: "376", Calling: "972", Names: map[string]string{"de": "Israel", "en": "Israel", "es": "Israel", "fr": "Israël", "it": "Israele", "ja": "イスラエル", "ko": "이스라엘", "pt": "Israel", "ru": "Израиль", "zh": "以色列"}},
+	{Alpha2: "IM", Alpha3: "IMN", Numeric: "833", Calling: "44", Names: map[string]string{"de": "Isle of Man", "en": "Isle of Man", "es": "Isla de Man", "fr": "Île de Man", "it": "Isola di Man", "ja": "マン島", "ko": "맨 섬", "pt": "Ilha de Man", "ru": "о-в Мэн", "zh": "马恩岛"}},
+	{Alpha2: "IN", Alpha3: "IND", Numeric: "356", Calling: "91", Names: map[string]string{"de": "Indien", "en": "India", "es": "India", "fr": "Inde", "it": "India", "ja": "インド", "ko": "인도", "pt": "Índia", "ru": "Индия", "zh": "印度"}},
+	{Alpha2: "IO", Alpha3: "IOT", Numeric: "086", Calling: "246", Names: map[string]string{"de": "Britisches Territorium im Indischen Ozean", "en": "British Indian Ocean Territory", "es": "Territorio Británico del Océano Índico", "fr": "Territoire britannique de l’océan Indien", "it": "Territorio britannico dell’Oceano Indiano", "ja": "英領インド洋地域", "ko": "영국령 인도양 식민지", "pt": "Território Britânico do Oceano Índico", "ru": "Британская территория в Индийском океане", "zh": "英属印度洋领地"}},
+	{Alpha2: "IQ", Alpha3: "IRQ", Numeric: "368", Calling: "964", Names: map[string]string{"de": "Irak", "en": "Iraq", "es": "Irak", "fr": "Irak", "it": "Iraq", "ja": "イラク", "ko": "이라크", "pt": "Iraque", "ru": "Ирак", "zh": "伊拉克"}},
+	{Alpha2: "IR", Alpha3: "IRN", Numeric: "364", Calling: "98", Names: map[string]string{"de": "Iran", "en": "Iran", "es": "Irán", "fr": "Iran", "it": "Iran", "ja": "イラン", "ko": "이란", "pt": "Irã", "ru": "Иран", "zh": "伊朗"}, Aliases: []string{"Islamic Republic of Iran"}},
+	{Alpha2: "IS", Alpha3: "ISL", Numeric: "352", Calling: "354", Names: map[string]string{"de": "Island", "en": "Iceland", "es": "Islandia", "fr": "Islande", "it": "Islanda", "ja": "アイスランド", "ko": "아이슬란드", "pt": "Islândia", "ru": "Исландия", "zh": "冰岛"}},
+	{Alpha2: "IT", Alpha3: "ITA", Numeric: "380", Calling: "39", Names: map[string]string{"de": "Italien", "en": "Italy", "es": "Italia", "fr": "Italie", "it": "Italia", "ja": "イタリア", "ko": "이탈리아", "pt": "Itália", "ru": "Италия", "zh": "意大利"}},
+	{Alpha2: "JE", Alpha3: "JEY", Numeric: "832", Calling: "44", Names: map[string]string{"de": "Jersey", "en": "Jersey", "es": "Jersey", "fr": "Jersey", "it": "Jersey", "ja": "ジャージー", "ko": "저지", "pt": "Jersey", "ru": "Джерси", "zh": "泽西岛"}},
+	{Alpha2: "JM", Alpha3: "JAM", Numeric: "388", Calling: "1", Names: map[string]string{"de": "Jamaika", "en": "Jamaica", "es": "Jamaica", "fr": "Jamaïque", "it": "Giamaica", "ja": "ジャマイカ", "ko": "자메이카", "pt": "Jamaica", "ru": "Ямайка", "zh": "牙买加"}},
+	{Alpha2: "JO", Alpha3: "JOR", Numeric: "400", Calling: "962", Names: map[string]string{"de": "Jordanien", "en": "Jordan", "es": "Jordania", "fr": "Jordanie", "it": "Giordania", "ja": "ヨルダン", "ko": "요르단", "pt": "Jordânia", "ru": "Иордания", "zh": "约旦"}},
+	{Alpha2: "JP", Alpha3: "JPN", Numeric: "392", Calling: "81", Names: map[string]string{"de": "Japan", "en": "Japan", "es": "Japón", "fr": "Japon", "it": "Giappone", "ja": "日本", "ko": "일본", "pt": "Japão", "ru": "Япония", "zh": "日本"}},
+	{Alpha2: "KE", Alpha3: "KEN", Numeric: "404", Calling: "254", Names: map[string]string{"de": "Kenia", "en": "Kenya", "es": "Kenia", "fr": "Kenya", "it": "Kenya", "ja": "ケニア", "ko": "케냐", "pt": "Quênia", "ru": "Кения", "zh": "肯尼亚"}},
+	{Alpha2: "KG", Alpha3: "KGZ", Numeric: "417", Calling: "996", Names: map[string]string{"de": "Kirgisistan", "en": "Kyrgyzstan", "es": "Kirguistán", "fr": "Kirghizistan", "it": "Kirghizistan", "ja": "キルギス", "ko": "키르기스스탄", "pt": "Quirguistão", "ru": "Киргизия", "zh": "吉尔吉斯斯坦"}},
+	{Alpha2: "KH", Alpha3: "KHM", Numeric: "116", Calling: "855", Names: map[string]string{"de": "Kambodscha", "en": "Cambodia", "es": "Camboya", "fr": "Cambodge", "it": "Cambogia", "ja": "カンボジア", "ko": "캄보디아", "pt": "Camboja", "ru": "Камбоджа", "zh": "柬埔寨"}},
+	{Alpha2: "KI", Alpha3: "KIR", Numeric: "296", Calling: "686", Names: map[string]string{"de": "Kiribati", "en": "Kiribati", "es": "Kiribati", "fr": "Kiribati", "it": "Kiribati", "ja": "キリバス", "ko": "키리바시", "pt": "Quiribati", "ru": "Кирибати", "zh": "基里巴斯"}},
+	{Alpha2: "KM", Alpha3: "COM", Numeric: "174", Calling: "269", Names: map[string]string{"de": "Komoren", "en": "Comoros", "es": "Comoras", "fr": "Comores", "it": "Comore", "ja": "コモロ", "ko": "코모로", "pt": "Comores", "ru": "Коморы", "zh": "科摩罗"}},
+	{Alpha2: "KN", Alpha3: "KNA", Numeric: "659", Calling: "1", Names: map[string]string{"de": "St. Kitts und Nevis", "en": "St. Kitts & Nevis", "es": "San Cristóbal y Nieves", "fr": "Saint-Christophe-et-Niévès", "it": "Saint Kitts e Nevis", "ja": "セントクリストファー・ネーヴィス", "ko": "세인트키츠 네비스", "pt": "São Cristóvão e Névis", "ru": "Сент-Китс и Невис", "zh": "圣基茨和尼维斯"}},
+	{Alpha2: "KP", Alpha3: "PRK", Numeric: "408", Calling: "850", Names: map[string]string{"de": "Nordkorea", "en": "North Korea", "es": "Corea del Norte", "fr": "Corée du Nord", "it": "Corea del Nord", "ja": "北朝鮮", "ko": "북한", "pt": "Coreia do Norte", "ru": "КНДР", "zh": "朝鲜"}, Aliases: []string{"DPRK", "Democratic People's Republic of Korea"}},
+	{Alpha2: "KR", Alpha3: "KOR", Numeric: "410", Calling: "82", Names: map[string]string{"de": "Südkorea", "en": "South Korea", "es": "Corea del Sur", "fr": "Corée du Sud", "it": "Corea del Sud", "ja": "韓国", "ko": "대한민국", "pt": "Coreia do Sul", "ru": "Республика Корея", "zh": "韩国"}, Aliases: []string{"Korea", "Republic of Korea"}},
+	{Alpha2: "KW", Alpha3: "KWT", Numeric: "414", Calling: "965", Names: map[string]string{"de": "Kuwait", "en": "Kuwait", "es": "Kuwait", "fr": "Koweït", "it": "Kuwait", "ja": "クウェート", "ko": "쿠웨이트", "pt": "Kuwait", "ru": "Кувейт", "zh": "科威特"}},
+	{Alpha2: "KY", Alpha3: "CYM", Numeric: "136", Calling: "1", Names: map[string]string{"de": "Kaimaninseln", "en": "Cayman Islands", "es": "Islas Caimán", "fr": "Îles Caïmans", "it": "Isole Cayman", "ja": "ケイマン諸島", "ko": "케이맨 제도", "pt": "Ilhas Cayman", "ru": "Каймановы о-ва", "zh": "开曼群岛"}},
+	{Alpha2: "KZ", Alpha3: "KAZ", Numeric: "398", Calling: "7", Names: map[string]string{"de": "Kasachstan", "en": "Kazakhstan", "es": "Kazajistán", "fr": "Kazakhstan", "it": "Kazakistan", "ja": "カザフスタン", "ko": "카자흐스탄", "pt": "Cazaquistão", "ru": "Казахстан", "zh": "哈萨克斯坦"}},
+	{Alpha2: "LA", Alpha3: "LAO", Numeric: "418", Calling: "856", Names: map[string]string{"de": "Laos", "en": "Laos", "es": "Laos", "fr": "Laos", "it": "Laos", "ja": "ラオス", "ko": "라오스", "pt": "Laos", "ru": "Лаос", "zh": "老挝"}, Aliases: []string{"Lao People's Democratic Republic"}},
+	{Alpha2: "LB", Alpha3: "LBN", Numeric: "422", Calling: "961", Names: map[string]string{"de": "Libanon", "en": "Lebanon", "es": "Líbano", "fr": "Liban", "it": "Libano", "ja": "レバノン", "ko": "레바논", "pt": "Líbano", "ru": "Ливан", "zh": "黎巴嫩"}},
+	{Alpha2: "LC", Alpha3: "LCA", Numeric: "662", Calling: "1", Names: map[string]string{"de": "St. Lucia", "en": "St. Lucia", "es": "Santa Lucía", "fr": "Sainte-Lucie", "it": "Saint Lucia", "ja": "セントルシア", "ko": "세인트루시아", "pt": "Santa Lúcia", "ru": "Сент-Люсия", "zh": "圣卢西亚"}},
+	{Alpha2: "LI", Alpha3: "LIE", Numeric: "438", Calling: "423", Names: map[string]string{"de": "Liechtenstein", "en": "Liechtenstein", "es": "Liechtenstein", "fr": "Liechtenstein", "it": "Liechtenstein", "ja": "リヒテンシュタイン", "ko": "리히텐슈타인", "pt": "Liechtenstein", "ru": "Лихтенштейн", "zh": "列支敦士登"}},
+	{Alpha2: "LK", Alpha3: "LKA", Numeric: "144", Calling: "94", Names: map[string]string{"de": "Sri Lanka", "en": "Sri Lanka", "es": "Sri Lanka", "fr": "Sri Lanka", "it": "Sri Lanka", "ja": "スリランカ", "ko": "스리랑카", "pt": "Sri Lanka", "ru": "Шри-Ланка", "zh": "斯里兰卡"}},
+	{Alpha2: "LR", Alpha3: "LBR", Numeric: "430", Calling: "231", Names: map[string]string{"de": "Liberia", "en": "Liberia", "es": "Liberia", "fr": "Libéria", "it": "Liberia", "ja": "リベリア", "ko": "라이베리아", "pt": "Libéria", "ru": "Либерия", "zh": "利比里亚"}},
+	{Alpha2: "LS", Alpha3: "LSO", Numeric: "426", Calling: "266", Names: map[string]string{"de": "Lesotho", "en": "Lesotho", "es": "Lesoto", "fr": "Lesotho", "it": "Lesotho", "ja": "レソト", "ko": "레소토", "pt": "Lesoto", "ru": "Лесото", "zh": "莱索托"}},
+	{Alpha2: "LT", Alpha3: "LTU", Numeric: "440", Calling: "370", Names: map[string]string{"de": "Litauen", "en": "Lithuania", "es": "Lituania", "fr": "Lituanie", "it": "Lituania", "ja": "リトアニア", "ko": "리투아니아", "pt": "Lituânia", "ru": "Литва", "zh": "立陶宛"}},
+	{Alpha2: "LU", Alpha3: "LUX", Numeric: "442", Calling: "352", Names: map[string]string{"de": "Luxemburg", "en": "Luxembourg", "es": "Luxemburgo", "fr": "Luxembourg", "it": "Lussemburgo", "ja": "ルクセンブルク", "ko": "룩셈부르크", "pt": "Luxemburgo", "ru": "Люксембург", "zh": "卢森堡"}},
+	{Alpha2: "LV", Alpha3: "LVA", Numeric: "428", Calling: "371", Names: map[string]string{"de": "Lettland", "en": "Latvia", "es": "Letonia", "fr": "Lettonie", "it": "Lettonia", "ja": "ラトビア", "ko": "라트비아", "pt": "Letônia", "ru": "Латвия", "zh": "拉脱维亚"}},
+	{Alpha2: "LY", Alpha3: "LBY", Numeric: "434", Calling: "218", Names: map[string]string{"de": "Libyen", "en": "Libya", "es": "Libia", "fr": "Libye", "it": "Libia", "ja": "リビア", "ko": "리비아", "pt": "Líbia", "ru": "Ливия", "zh": "利比亚"}},
+	{Alpha2: "MA", Alpha3: "MAR", Numeric: "504", Calling: "212", Names: map[string]string{"de": "Marokko", "en": "Morocco", "es": "Marruecos", "fr": "Maroc", "it": "Marocco", "ja": "モロッコ", "ko": "모로코", "pt": "Marrocos", "ru": "Марокко", "zh": "摩洛哥"}},
+	{Alpha2: "MC", Alpha3: "MCO", Numeric: "492", Calling: "377", Names: map[string]string{"de": "Monaco", "en": "Monaco", "es": "Mónaco", "fr": "Monaco", "it": "Monaco", "ja": "モナコ", "ko": "모나코", "pt": "Mônaco", "ru": "Монако", "zh": "摩纳哥"}},
+	{Alpha2: "MD", Alpha3: "MDA", Numeric: "498", Calling: "373", Names: map[string]string{"de": "Republik Moldau", "en": "Moldova", "es": "Moldavia", "fr": "Moldavie", "it": "Moldavia", "ja": "モルドバ", "ko": "몰도바", "pt": "Moldávia", "ru": "Молдова", "zh": "摩尔多瓦"}, Aliases: []string{"Republic of Moldova"}},
+	{Alpha2: "ME", Alpha3: "MNE", Numeric: "499", Calling: "382", Names: map[string]string{"de": "Montenegro", "en": "Montenegro", "es": "Montenegro", "fr": "Monténégro", "it": "Montenegro", "ja": "モンテネグロ", "ko": "몬테네그로", "pt": "Montenegro", "ru": "Черногория", "zh": "黑山"}},
+	{Alpha2: "MF", Alpha3: "MAF", Numeric: "663", Calling: "590", Names: map[string]string{"de": "St. Martin", "en": "St. Martin", "es": "San Martín", "fr": "Saint-Martin", "it": "Saint Martin", "ja": "サン・マルタン", "ko": "생마르탱", "pt": "São Martinho", "ru": "Сен-Мартен", "zh": "法属圣马丁"}},
+	{Alpha2: "MG", Alpha3: "MDG", Numeric: "450", Calling: "261", Names: map[string]string{"de": "Madagaskar", "en": "Madagascar", "es": "Madagascar", "fr": "Madagascar", "it": "Madagascar", "ja": "マダガスカル", "ko": "마다가스카르", "pt": "Madagascar", "ru": "Мадагаскар", "zh": "马达加斯加"}},
+	{Alpha2: "MH", Alpha3: "MHL", Numeric: "584", Calling: "692", Names: map[string]string{"de": "Marshallinseln", "en": "Marshall Islands", "es": "Islas Marshall", "fr": "Îles Marshall", "it": "Isole Marshall", "ja": "マーシャル諸島", "ko": "마셜 제도", "pt": "Ilhas Marshall", "ru": "Маршалловы Острова", "zh": "马绍尔群岛"}},
+	{Alpha2: "MK", Alpha3: "MKD", Numeric: "807", Calling: "389", Names: map[string]string{"de": "Mazedonien", "en": "North Macedonia", "es": "Macedonia", "fr": "Macédoine", "it": "Repubblica di Macedonia", "ja": "マケドニア", "ko": "마케도니아", "pt": "Macedônia", "ru": "Македония", "zh": "马其顿"}, Aliases: []string{"Macedonia"}},
+	{Alpha2: "ML", Alpha3: "MLI", Numeric: "466", Calling: "223", Names: map[string]string{"de": "Mali", "en": "Mali", "es": "Mali", "fr": "Mali", "it": "Mali", "ja": "マリ", "ko": "말리", "pt": "Mali", "ru": "Мали", "zh": "马里"}},
+	{Alpha2: "MM", Alpha3: "MMR", Numeric: "104", Calling: "95", Names: map[string]string{"de": "Myanmar", "en": "Myanmar (Burma)", "es": "Myanmar (Birmania)", "fr": "Myanmar (Birmanie)", "it": "Myanmar (Birmania)", "ja": "ミャンマー (ビルマ)", "ko": "미얀마", "pt": "Mianmar (Birmânia)", "ru": "Мьянма (Бирма)", "zh": "缅甸"}, Aliases: []string{"Burma"}},
+	{Alpha2: "MN", Alpha3: "MNG", Numeric: "496", Calling: "976", Names: map[string]string{"de": "Mongolei", "en": "Mongolia", "es": "Mongolia", "fr": "Mongolie", "it": "Mongolia", "ja": "モンゴル", "ko": "몽골", "pt": "Mongólia", "ru": "Монголия", "zh": "蒙古"}},
+	{Alpha2: "MO", Alpha3: "MAC", Numeric: "446", Calling: "853", Names: map[string]string{"de": "Sonderverwaltungsregion Macau", "en": "Macau SAR China", "es": "RAE de Macao (China)", "fr": "R.A.S. chinoise de Macao", "it": "RAS di Macao", "ja": "中華人民共和国マカオ特別行政区", "ko": "마카오(중국 특별행정구)", "pt": "Macau, RAE da China", "ru": "Макао (САР)", "zh": "中国澳门特别行政区"}, Aliases: []string{"Macau", "Macao"}},
+	{Alpha2: "MP", Alpha3: "MNP", Numeric: "580", Calling: "1", Names: map[string]string{"de": "Nördliche Marianen", "en": "Northern Mariana Islands", "es": "Islas Marianas del Norte", "fr": "Îles Mariannes du Nord", "it": "Isole Marianne settentrionali", "ja": "北マリアナ諸島", "ko": "북마리아나제도", "pt": "Ilhas Marianas do Norte", "ru": "Северные Марианские о-ва", "zh": "北马里亚纳群岛"}},
+	{Alpha2: "MQ", Alpha3: "MTQ", Numeric: "474", Calling: "596", Names: map[string]string{"de": "Martinique", "en": "Martinique", "es": "Martinica", "fr": "Martinique", "it": "Martinica", "ja": "マルティニーク", "ko": "마르티니크", "pt": "Martinica", "ru": "Мартиника", "zh": "马提尼克"}},
+	{Alpha2: "MR", Alpha3: "MRT", Numeric: "478", Calling: "222", Names: map[string]string{"de": "Mauretanien", "en": "Mauritania", "es": "Mauritania", "fr": "Mauritanie", "it": "Mauritania", "ja": "モーリタニア", "ko": "모리타니", "pt": "Mauritânia", "ru": "Мавритания", "zh": "毛里塔尼亚"}},
+	{Alpha2: "MS", Alpha3: "MSR", Numeric: "500", Calling: "1", Names: map[string]string{"de": "Montserrat", "en": "Montserrat", "es": "Montserrat", "fr": "Montserrat", "it": "Montserrat", "ja": "モントセラト", "ko": "몬트세라트", "pt": "Montserrat", "ru": "Монтсеррат", "zh": "蒙特塞拉特"}},
+	{Alpha2: "MT", Alpha3: "MLT", Numeric: "470", Calling: "356", Names: map[string]string{"de": "Malta", "en": "Malta", "es": "Malta", "fr": "Malte", "it": "Malta", "ja": "マルタ", "ko": "몰타", "pt": "Malta", "ru": "Мальта", "zh": "马耳他"}},
+	{Alpha2: "MU", Alpha3: "MUS", Numeric: "480", Calling: "230", Names: map[string]string{"de": "Mauritius", "en": "Mauritius", "es": "Mauricio", "fr": "Maurice", "it": "Mauritius", "ja": "モーリシャス", "ko": "모리셔스", "pt": "Maurício", "ru": "Маврикий", "zh": "毛里求斯"}},
+	{Alpha2: "MV", Alpha3: "MDV", Numeric: "462", Calling: "960", Names: map[string]string{"de": "Malediven", "en": "Maldives", "es": "Maldivas", "fr": "Maldives", "it": "Maldive", "ja": "モルディブ", "ko": "몰디브", "pt": "Maldivas", "ru": "Мальдивы", "zh": "马尔代夫"}},
+	{Alpha2: "MW", Alpha3: "MWI", Numeric: "454", Calling: "265", Names: map[string]string{"de": "Malawi", "en": "Malawi", "es": "Malaui", "fr": "Malawi", "it": "Malawi", "ja": "マラウイ", "ko": "말라위", "pt": "Malaui", "ru": "Малави", "zh": "马拉维"}},
+	{Alpha2: "MX", Alpha3: "MEX", Numeric: "484", Calling: "52", Names: map[string]string{"de": "Mexiko", "en": "Mexico", "es": "México", "fr": "Mexique", "it": "Messico", "ja": "メキシコ", "ko": "멕시코", "pt": "México", "ru": "Мексика", "zh": "墨西哥"}},
+	{Alpha2: "MY", Alpha3: "MYS", Numeric: "458", Calling: "60", Names: map[string]string{"de": "Malaysia", "en": "Malaysia", "es": "Malasia", "fr": "Malaisie", "it": "Malaysia", "ja": "マレーシア", "ko": "말레이시아", "pt": "Malásia", "ru": "Малайзия", "zh": "马来西亚"}},
+	{Alpha2: "MZ", Alpha3: "MOZ", Numeric: "508", Calling: "258", Names: map[string]string{"de": "Mosambik", "en": "Mozambique", "es": "Mozambique", "fr": "Mozambique", "it": "Mozambico", "ja": "モザンビーク", "ko": "모잠비크", "pt": "Moçambique", "ru": "Мозамбик", "zh": "莫桑比克"}},
+	{Alpha2: "NA", Alpha3: "NAM", Numeric: "516", Calling: "264", Names: map[string]string{"de": "Namibia", "en": "Namibia", "es": "Namibia", "fr": "Namibie", "it": "Namibia", "ja": "ナミビア", "ko": "나미비아", "pt": "Namíbia", "ru": "Намибия", "zh": "纳米比亚"}},
+	{Alpha2: "NC", Alpha3: "NCL", Numeric: "540", Calling: "687", Names: map[string]string{"de": "Neukaledonien", "en": "New Caledonia", "es": "Nueva Caledonia", "fr": "Nouvelle-Calédonie", "it": "Nuova Caledonia", "ja": "ニューカレドニア", "ko": "뉴칼레도니아", "pt": "Nova Caledônia", "ru": "Новая Каледония", "zh": "新喀里多尼亚"}},
+	{Alpha2: "NE", Alpha3: "NER", Numeric: "562", Calling: "227", Names: map[string]string{"de": "Niger", "en": "Niger", "es": "Níger", "fr": "Niger", "it": "Niger", "ja": "ニジェール", "ko": "니제르", "pt": "Níger", "ru": "Нигер", "zh": "尼日尔"}},
+	{Alpha2: "NF", Alpha3: "NFK", Numeric: "574", Calling: "672", Names: map[string]string{"de": "Norfolkinsel", "en": "Norfolk Island", "es": "Isla Norfolk", "fr": "Île Norfolk", "it": "Isola Norfolk", "ja": "ノーフォーク島", "ko": "노퍽섬", "pt": "Ilha Norfolk", "ru": "о-в Норфолк", "zh": "诺福克岛"}},
+	{Alpha2: "NG", Alpha3: "NGA", Numeric: "566", Calling: "234", Names: map[string]string{"de": "Nigeria", "en": "Nigeria", "es": "Nigeria", "fr": "Nigéria", "it": "Nigeria", "ja": "ナイジェリア", "ko": "나이지리아", "pt": "Nigéria", "ru": "Нигерия", "zh": "尼日利亚"}},
+	{Alpha2: "NI", Alpha3: "NIC", Numeric: "558", Calling: "505", Names: map[string]string{"de": "Nicaragua", "en": "Nicaragua", "es": "Nicaragua", "fr": "Nicaragua", "it": "Nicaragua", "ja": "ニカラグア", "ko": "니카라과", "pt": "Nicarágua", "ru": "Никарагуа", "zh": "尼加拉瓜"}},
+	{Alpha2: "NL", Alpha3: "NLD", Numeric: "528", Calling: "31", Names: map[string]string{"de": "Niederlande", "en": "Netherlands", "es": "Países Bajos", "fr": "Pays-Bas", "it": "Paesi Bassi", "ja": "オランダ", "ko": "네덜란드", "pt": "Holanda", "ru": "Нидерланды", "zh": "荷兰"}, Aliases: []string{"Holland"}},
+	{Alpha2: "NO", Alpha3: "NOR", Numeric: "578", Calling: "47", Names: map[string]string{"de": "Norwegen", "en": "Norway", "es": "Noruega", "fr": "Norvège", "it": "Norvegia", "ja": "ノルウェー", "ko": "노르웨이", "pt": "Noruega", "ru": "Норвегия", "zh": "挪威"}},
+	{Alpha2: "NP", Alpha3: "NPL", Numeric: "524", Calling: "977", Names: map[string]string{"de": "Nepal", "en": "Nepal", "es": "Nepal", "fr": "Népal", "it": "Nepal", "ja": "ネパール", "ko": "네팔", "pt": "Nepal", "ru": "Непал", "zh": "尼泊尔"}},
+	{Alpha2: "NR", Alpha3: "NRU", Numeric: "520", Calling: "674", Names: map[string]string{"de": "Nauru", "en": "Nauru", "es": "Nauru", "fr": "Nauru", "it": "Nauru", "ja": "ナウル", "ko": "나우루", "pt": "Nauru", "ru": "Науру", "zh": "瑙鲁"}},
+	{Alpha2: "NU", Alpha3: "NIU", Numeric: "570", Calling: "683", Names: map[string]string{"de": "Niue", "en": "Niue", "es": "Niue", "fr": "Niue", "it": "Niue", "ja": "ニウエ", "ko": "니우에", "pt": "Niue", "ru": "Ниуэ", "zh": "纽埃"}},
+	{Alpha2: "NZ", Alpha3: "NZL", Numeric: "554", Calling: "64", Names: map[string]string{"de": "Neuseeland", "en": "New Zealand", "es": "Nueva Zelanda", "fr": "Nouvelle-Zélande", "it": "Nuova Zelanda", "ja": "ニュージーランド", "ko": "뉴질랜드", "pt": "Nova Zelândia", "ru": "Новая Зеландия", "zh": "新西兰"}},
+	{Alpha2: "OM", Alpha3: "OMN", Numeric: "512", Calling: "968", Names: map[string]string{"de": "Oman", "en": "Oman", "es": "Omán", "fr": "Oman", "it": "Oman", "ja": "オマーン", "ko": "오만", "pt": "Omã", "ru": "Оман", "zh": "阿曼"}},
+	{Alpha2: "PA", Alpha3: "PAN", Numeric: "591", Calling: "507", Names: map[string]string{"de": "Panama", "en": "Panama", "es": "Panamá", "fr": "Panama", "it": "Panamá", "ja": "パナマ", "ko": "파나마", "pt": "Panamá", "ru": "Панама", "zh": "巴拿马"}},
+	{Alpha2: "PE", Alpha3: "PER", Numeric: "604", Calling: "51", Names: map[string]string{"de": "Peru", "en": "Peru", "es": "Perú", "fr": "Pérou", "it": "Perù", "ja": "ペルー", "ko": "페루", "pt": "Peru", "ru": "Перу", "zh": "秘鲁"}},
+	{Alpha2: "PF", Alpha3: "PYF", Numeric: "258", Calling: "689", Names: map[string]string{"de": "Französisch-Polynesien", "en": "French Polynesia", "es": "Polinesia Francesa", "fr": "Polynésie française", "it": "Polinesia francese", "ja": "仏領ポリネシア", "ko": "프랑스령 폴리네시아", "pt": "Polinésia Francesa", "ru": "Французская Полинезия", "zh": "法属波利尼西亚"}},
+	{Alpha2: "PG", Alpha3: "PNG", Numeric: "598", Calling: "675", Names: map[string]string{"de": "Papua-Neuguinea", "en": "Papua New Guinea", "es": "Papúa Nueva Guinea", "fr": "Papouasie-Nouvelle-Guinée", "it": "Papua Nuova Guinea", "ja": "パプアニューギニア", "ko": "파푸아뉴기니", "pt": "Papua-Nova Guiné", "ru": "Папуа — Новая Гвинея", "zh": "巴布亚新几内亚"}},
+	{Alpha2: "PH", Alpha3: "PHL", Numeric: "608", Calling: "63", Names: map[string]string{"de": "Philippinen", "en": "Philippines", "es": "Filipinas", "fr": "Philippines", "it": "Filippine", "ja": "フィリピン", "ko": "필리핀", "pt": "Filipinas", "ru": "Филиппины", "zh": "菲律宾"}},
+	{Alpha2: "PK", Alpha3: "PAK", Numeric: "586", Calling: "92", Names: map[string]string{"de": "Pakistan", "en": "Pakistan", "es": "Pakistán", "fr": "Pakistan", "it": "Pakistan", "ja": "パキスタン", "ko": "파키스탄", "pt": "Paquistão", "ru": "Пакистан", "zh": "巴基斯坦"}},
+	{Alpha2: "PL", Alpha3: "POL", Numeric: "616", Calling: "48", Names: map[string]string{"de": "Polen", "en": "Poland", "es": "Polonia", "fr": "Pologne", "it": "Polonia", "ja": "ポーランド", "ko": "폴란드", "pt": "Polônia", "ru": "Польша", "zh": "波兰"}},
+	{Alpha2: "PM", Alpha3: "SPM", Numeric: "666", Calling: "508", Names: map[string]string{"de": "St. Pierre und Miquelon", "en": "St. Pierre & Miquelon", "es": "San Pedro y Miquelón", "fr": "Saint-Pierre-et-Miquelon", "it": "Saint-Pierre e Miquelon", "ja": "サンピエール島・ミクロン島", "ko": "생피에르 미클롱", "pt": "São Pedro e Miquelão", "ru": "Сен-Пьер и Микелон", "zh": "圣皮埃尔和密克隆群岛"}},
+	{Alpha2: "PN", Alpha3: "PCN", Numeric: "612", Calling: "64", Names: map[string]string{"de": "Pitcairninseln", "en": "Pitcairn Islands", "es": "Islas Pitcairn", "fr": "Îles Pitcairn", "it": "Isole Pitcairn", "ja": "ピトケアン諸島", "ko": "핏케언 섬", "pt": "Ilhas Pitcairn", "ru": "острова Питкэрн", "zh": "皮特凯恩群岛"}},
+	{Alpha2: "PR", Alpha3: "PRI", Numeric: "630", Calling: "1", Names: map[string]string{"de": "Puerto Rico", "en": "Puerto Rico", "es": "Puerto Rico", "fr": "Porto Rico", "it": "Portorico", "ja": "プエルトリコ", "ko": "푸에르토리코", "pt": "Porto Rico", "ru": "Пуэрто-Рико", "zh": "波多黎各"}},
+	{Alpha2: "PS", Alpha3: "PSE", Numeric: "275", Calling: "970", Names: map[string]string{"de": "Palästinensische Autonomiegebiete", "en": "Palestinian Territories", "es": "Territorios Palestinos", "fr": "Territoires palestiniens", "it": "Territori palestinesi", "ja": "パレスチナ自治区", "ko": "팔레스타인 지구", "pt": "Territórios palestinos", "ru": "Палестинские территории", "zh": "巴勒斯坦领土"}, Aliases: []string{"Palestine"}},
+	{Alpha2: "PT", Alpha3: "PRT", Numeric: "620", Calling: "351", Names: map[string]string{"de": "Portugal", "en": "Portugal", "es": "Portugal", "fr": "Portugal", "it": "Portogallo", "ja": "ポルトガル", "ko": "포르투갈", "pt": "Portugal", "ru": "Португалия", "zh": "葡萄牙"}},
+	{Alpha2: "PW", Alpha3: "PLW", Numeric: "585", Calling: "680", Names: map[string]string{"de": "Palau", "en": "Palau", "es": "Palaos", "fr": "Palaos", "it": "Palau", "ja": "パラオ", "ko": "팔라우", "pt": "Palau", "ru": "Палау", "zh": "帕劳"}},
+	{Alpha2: "PY", Alpha3: "PRY", Numeric: "600", Calling: "595", Names: map[string]string{"de": "Paraguay", "en": "Paraguay", "es": "Paraguay", "fr": "Paraguay", "it": "Paraguay", "ja": "パラグアイ", "ko": "파라과이", "pt": "Paraguai", "ru": "Парагвай", "zh": "巴拉圭"}},
+	{Alpha2: "QA", Alpha3: "QAT", Numeric: "634", Calling: "974", Names: map[string]string{"de": "Katar", "en": "Qatar", "es": "Catar", "fr": "Qatar", "it": "Qatar", "ja": "カタール", "ko": "카타르", "pt": "Catar", "ru": "Катар", "zh": "卡塔尔"}},
+	{Alpha2: "RE", Alpha3: "REU", Numeric: "638", Calling: "262", Names: map[string]string{"de": "Réunion", "en": "Réunion", "es": "Reunión", "fr": "La Réunion", "it": "Riunione", "ja": "レユニオン", "ko": "리유니온", "pt": "Reunião", "ru": "Реюньон", "zh": "留尼汪"}},
+	{Alpha2: "RO", Alpha3: "ROU", Numeric: "642", Calling: "40", Names: map[string]string{"de": "Rumänien", "en": "Romania", "es": "Rumanía", "fr": "Roumanie", "it": "Romania", "ja": "ルーマニア", "ko": "루마니아", "pt": "Romênia", "ru": "Румыния", "zh": "罗马尼亚"}},
+	{Alpha2: "RS", Alpha3: "SRB", Numeric: "688", Calling: "381", Names: map[string]string{"de": "Serbien", "en": "Serbia", "es": "Serbia", "fr": "Serbie", "it": "Serbia", "ja": "セルビア", "ko": "세르비아", "pt": "Sérvia", "ru": "Сербия", "zh": "塞尔维亚"}},
+	{Alpha2: "RU", Alpha3: "RUS", Numeric: "643", Calling: "7", Names: map[string]string{"de": "Russland", "en": "Russia", "es": "Rusia", "fr": "Russie", "it": "Russia", "ja": "ロシア", "ko": "러시아", "pt": "Rússia", "ru": "Россия", "zh": "俄罗斯"}, Aliases: []string{"Russian Federation"}},
+	{Alpha2: "RW", Alpha3: "RWA", Numeric: "646", Calling: "250", Names: map[string]string{"de": "Ruanda", "en": "Rwanda", "es": "Ruanda", "fr": "Rwanda", "it": "Ruanda", "ja": "ルワンダ", "ko": "르완다", "pt": "Ruanda", "ru": "Руанда", "zh": "卢旺达"}},
+	{Alpha2: "SA", Alpha3: "SAU", Numeric: "682", Calling: "966", Names: map[string]string{"de": "Saudi-Arabien", "en": "Saudi Arabia", "es": "Arabia Saudí", "fr": "Arabie saoudite", "it": "Arabia Saudita", "ja": "サウジアラビア", "ko": "사우디아라비아", "pt": "Arábia Saudita", "ru": "Саудовская Аравия", "zh": "沙特阿拉伯"}},
+	{Alpha2: "SB", Alpha3: "SLB", Numeric: "090", Calling: "677", Names: map[string]string{"de": "Salomonen", "en": "Solomon Islands", "es": "Islas Salomón", "fr": "Îles Salomon", "it": "Isole Salomone", "ja": "ソロモン諸島", "ko": "솔로몬 제도", "pt": "Ilhas Salomão", "ru": "Соломоновы Острова", "zh": "所罗门群岛"}},
+	{Alpha2: "SC", Alpha3: "SYC", Numeric: "690", Calling: "248", Names: map[string]string{"de": "Seychellen", "en": "Seychelles", "es": "Seychelles", "fr": "Seychelles", "it": "Seychelles", "ja": "セーシェル", "ko": "세이셸", "pt": "Seicheles", "ru": "Сейшельские Острова", "zh": "塞舌尔"}},
+	{Alpha2: "SD", Alpha3: "SDN", Numeric: "729", Calling: "249", Names: map[string]string{"de": "Sudan", "en": "Sudan", "es": "Sudán", "fr": "Soudan", "it": "Sudan", "ja": "スーダン", "ko": "수단", "pt": "Sudão", "ru": "Судан", "zh": "苏丹"}},
+	{Alpha2: "SE", Alpha3: "SWE", Numeric: "752", Calling: "46", Names: map[string]string{"de": "Schweden", "en": "Sweden", "es": "Suecia", "fr": "Suède", "it": "Svezia", "ja": "スウェーデン", "ko": "스웨덴", "pt": "Suécia", "ru": "Швеция", "zh": "瑞典"}},
+	{Alpha2: "SG", Alpha3: "SGP", Numeric: "702", Calling: "65", Names: map[string]string{"de": "Singapur", "en": "Singapore", "es": "Singapur", "fr": "Singapour", "it": "Singapore", "ja": "シンガポール", "ko": "싱가포르", "pt": "Singapura", "ru": "Сингапур", "zh": "新加坡"}},
+	{Alpha2: "SH", Alpha3: "SHN", Numeric: "654", Calling: "290", Names: map[string]string{"de": "St. Helena", "en": "St. Helena", "es": "Santa Elena", "fr": "Sainte-Hélène", "it": "Sant’Elena", "ja": "セントヘレナ", "ko": "세인트헬레나", "pt": "Santa Helena", "ru": "о-в Св. Елены", "zh": "圣赫勒拿"}},
+	{Alpha2: "SI", Alpha3: "SVN", Numeric: "705", Calling: "386", Names: map[string]string{"de": "Slowenien", "en": "Slovenia", "es": "Eslovenia", "fr": "Slovénie", "it": "Slovenia", "ja": "スロベニア", "ko": "슬로베니아", "pt": "Eslovênia", "ru": "Словения", "zh": "斯洛文尼亚"}},
+	{Alpha2: "SJ", Alpha3: "SJM", Numeric: "744", Calling: "47", Names: map[string]string{"de": "Spitzbergen und Jan Mayen", "en": "Svalbard & Jan Mayen", "es": "Svalbard y Jan Mayen", "fr": "Svalbard et Jan Mayen", "it": "Svalbard e Jan Mayen", "ja": "スバールバル諸島・ヤンマイエン島", "ko": "스발바르제도-얀마웬섬", "pt": "Svalbard e Jan Mayen", "ru": "Шпицберген и Ян-Майен", "zh": "斯瓦尔巴和扬马延"}},
+	{Alpha2: "SK", Alpha3: "SVK", Numeric: "703", Calling: "421", Names: map[string]string{"de": "Slowakei", "en": "Slovakia", "es": "Eslovaquia", "fr": "Slovaquie", "it": "Slovacchia", "ja": "スロバキア", "ko": "슬로바키아", "pt": "Eslováquia", "ru": "Словакия", "zh": "斯洛伐克"}},
+	{Alpha2: "SL", Alpha3: "SLE", Numeric: "694", Calling: "232", Names: map[string]string{"de": "Sierra Leone", "en": "Sierra Leone", "es": "Sierra Leona", "fr": "Sierra Leone", "it": "Sierra Leone", "ja": "シエラレオネ", "ko": "시에라리온", "pt": "Serra Leoa", "ru": "Сьерра-Леоне", "zh": "塞拉利昂"}},
+	{Alpha2: "SM", Alpha3: "SMR", Numeric: "674", Calling: "378", Names: map[string]string{"de": "San Marino", "en": "San Marino", "es": "San Marino", "fr": "Saint-Marin", "it": "San Marino", "ja": "サンマリノ", "ko": "산마리노", "pt": "San Marino", "ru": "Сан-Марино", "zh": "圣马力诺"}},
+	{Alpha2: "SN", Alpha3: "SEN", Numeric: "686", Calling: "221", Names: map[string]string{"de": "Senegal", "en": "Senegal", "es": "Senegal", "fr": "Sénégal", "it": "Senegal", "ja": "セネガル", "ko": "세네갈", "pt": "Senegal", "ru": "Сенегал", "zh": "塞内加尔"}},
+	{Alpha2: "SO", Alpha3: "SOM", Numeric: "706", Calling: "252", Names: map[string]string{"de": "Somalia", "en": "Somalia", "es": "Somalia", "fr": "Somalie", "it": "Somalia", "ja": "ソマリア", "ko": "소말리아", "pt": "Somália", "ru": "Сомали", "zh": "索马里"}},
+	{Alpha2: "SR", Alpha3: "SUR", Numeric: "740", Calling: "597", Names: map[string]string{"de": "Suriname", "en": "Suriname", "es": "Surinam", "fr": "Suriname", "it": "Suriname", "ja": "スリナム", "ko": "수리남", "pt": "Suriname", "ru": "Суринам", "zh": "苏里南"}},
+	{Alpha2: "SS", Alpha3: "SSD", Numeric: "728", Calling: "211", Names: map[string]string{"de": "Südsudan", "en": "South Sudan", "es": "Sudán del Sur", "fr": "Soudan du Sud", "it": "Sud Sudan", "ja": "南スーダン", "ko": "남수단", "pt": "Sudão do Sul", "ru": "Южный Судан", "zh": "南苏丹"}},
+	{Alpha2: "ST", Alpha3: "STP", Numeric: "678", Calling: "239", Names: map[string]string{"de": "São Tomé und Príncipe", "en": "São Tomé & Príncipe", "es": "Santo Tomé y Príncipe", "fr": "Sao Tomé-et-Principe", "it": "São Tomé e Príncipe", "ja": "サントメ・プリンシペ", "ko": "상투메 프린시페", "pt": "São Tomé e Príncipe", "ru": "Сан-Томе и Принсипи", "zh": "圣多美和普林西比"}},
+	{Alpha2: "SV", Alpha3: "SLV", Numeric: "222", Calling: "503", Names: map[string]string{"de": "El Salvador", "en": "El Salvador", "es": "El Salvador", "fr": "Salvador", "it": "El Salvador", "ja": "エルサルバドル", "ko": "엘살바도르", "pt": "El Salvador", "ru": "Сальвадор", "zh": "萨尔瓦多"}},
+	{Alpha2: "SX", Alpha3: "SXM", Numeric: "534", Calling: "1", Names: map[string]string{"de": "Sint Maarten", "en": "Sint Maarten", "es": "Sint Maarten", "fr": "Saint-Martin (partie néerlandaise)", "it": "Sint Maarten", "ja": "シント・マールテン", "ko": "신트마르턴", "pt": "Sint Maarten", "ru": "Синт-Мартен", "zh": "荷属圣马丁"}},
+	{Alpha2: "SY", Alpha3: "SYR", Numeric: "760", Calling: "963", Names: map[string]string{"de": "Syrien", "en": "Syria", "es": "Siria", "fr": "Syrie", "it": "Siria", "ja": "シリア", "ko": "시리아", "pt": "Síria", "ru": "Сирия", "zh": "叙利亚"}, Aliases: []string{"Syrian Arab Republic"}},
+	{Alpha2: "SZ", Alpha3: "SWZ", Numeric: "748", Calling: "268", Names: map[string]string{"de": "Swasiland", "en": "Eswatini", "es": "Suazilandia", "fr": "Swaziland", "it": "Swaziland", "ja": "スワジランド", "ko": "스와질란드", "pt": "Suazilândia", "ru": "Свазиленд", "zh": "斯威士兰"}, Aliases: []string{"Swaziland"}},
+	{Alpha2: "TC", Alpha3: "TCA", Numeric: "796", Calling: "1", Names: map[string]string{"de": "Turks- und Caicosinseln", "en": "Turks & Caicos Islands", "es": "Islas Turcas y Caicos", "fr": "Îles Turques-et-Caïques", "it": "Isole Turks e Caicos", "ja": "タークス・カイコス諸島", "ko": "터크스 케이커스 제도", "pt": "Ilhas Turks e Caicos", "ru": "о-ва Тёркс и Кайкос", "zh": "特克斯和凯科斯群岛"}},
+	{Alpha2: "TD", Alpha3: "TCD", Numeric: "148", Calling: "235", Names: map[string]string{"de": "Tschad", "en": "Chad", "es": "Chad", "fr": "Tchad", "it": "Ciad", "ja": "チャド", "ko": "차드", "pt": "Chade", "ru": "Чад", "zh": "乍得"}},
+	{Alpha2: "TF", Alpha3: "ATF", Numeric: "260", Calling: "262", Names: map[string]string{"de": "Französische Süd- und Antarktisgebiete", "en": "French Southern Territories", "es": "Territorios Australes Franceses", "fr": "Terres australes françaises", "it": "Terre australi francesi", "ja": "仏領極南諸島", "ko": "프랑스 남부 지방", "pt": "Territórios Franceses do Sul", "ru": "Французские Южные территории", "zh": "法属南部领地"}},
+	{Alpha2: "TG", Alpha3: "TGO", Numeric: "768", Calling: "228", Names: map[string]string{"de": "Togo", "en": "Togo", "es": "Togo", "fr": "Togo", "it": "Togo", "ja": "トーゴ", "ko": "토고", "pt": "Togo", "ru": "Того", "zh": "多哥"}},
+	{Alpha2: "TH", Alpha3: "THA", Numeric: "764", Calling: "66", Names: map[string]string{"de": "Thailand", "en": "Thailand", "es": "Tailandia", "fr": "Thaïlande", "it": "Thailandia", "ja": "タイ", "ko": "태국", "pt": "Tailândia", "ru": "Таиланд", "zh": "泰国"}},
+	{Alpha2: "TJ", Alpha3: "TJK", Numeric: "762", Calling: "992", Names: map[string]string{"de": "Tadschikistan", "en": "Tajikistan", "es": "Tayikistán", "fr": "Tadjikistan", "it": "Tagikistan", "ja": "タジキスタン", "ko": "타지키스탄", "pt": "Tadjiquistão", "ru": "Таджикистан", "zh": "塔吉克斯坦"}},
+	{Alpha2: "TK", Alpha3: "TKL", Numeric: "772", Calling: "690", Names: map[string]string{"de": "Tokelau", "en": "Tokelau", "es": "Tokelau", "fr": "Tokélaou", "it": "Tokelau", "ja": "トケラウ", "ko": "토켈라우", "pt": "Tokelau", "ru": "Токелау", "zh": "托克劳"}},
+	{Alpha2: "TL", Alpha3: "TLS", Numeric: "626", Calling: "670", Names: map[string]string{"de": "Timor-Leste", "en": "Timor-Leste", "es": "Timor-Leste", "fr": "Timor oriental", "it": "Timor Est", "ja": "東ティモール", "ko": "동티모르", "pt": "Timor-Leste", "ru": "Восточный Тимор", "zh": "东帝汶"}, Aliases: []string{"East Timor"}},
+	{Alpha2: "TM", Alpha3: "TKM", Numeric: "795", Calling: "993", Names: map[string]string{"de": "Turkmenistan", "en": "Turkmenistan", "es": "Turkmenistán", "fr": "Turkménistan", "it": "Turkmenistan", "ja": "トルクメニスタン", "ko": "투르크메니스탄", "pt": "Turcomenistão", "ru": "Туркменистан", "zh": "土库曼斯坦"}},
+	{Alpha2: "TN", Alpha3: "TUN", Numeric: "788", Calling: "216", Names: map[string]string{"de": "Tunesien", "en": "Tunisia", "es": "Túnez", "fr": "Tunisie", "it": "Tunisia", "ja": "チュニジア", "ko": "튀니지", "pt": "Tunísia", "ru": "Тунис", "zh": "突尼斯"}},
+	{Alpha2: "TO", Alpha3: "TON", Numeric: "776", Calling: "676", Names: map[string]string{"de": "Tonga", "en": "Tonga", "es": "Tonga", "fr": "Tonga", "it": "Tonga", "ja": "トンガ", "ko": "통가", "pt": "Tonga", "ru": "Тонга", "zh": "汤加"}},
+	{Alpha2: "TR", Alpha3: "TUR", Numeric: "792", Calling: "90", Names: map[string]string{"de": "Türkei", "en": "Türkiye", "es": "Turquía", "fr": "Turquie", "it": "Turchia", "ja": "トルコ", "ko": "터키", "pt": "Turquia", "ru": "Турция", "zh": "土耳其"}, Aliases: []string{"Turkey"}},
+	{Alpha2: "TT", Alpha3: "TTO", Numeric: "780", Calling: "1", Names: map[string]string{"de": "Trinidad und Tobago", "en": "Trinidad & Tobago", "es": "Trinidad y Tobago", "fr": "Trinité-et-Tobago", "it": "Trinidad e Tobago", "ja": "トリニダード・トバゴ", "ko": "트리니다드 토바고", "pt": "Trinidad e Tobago", "ru": "Тринидад и Тобаго", "zh": "特立尼达和多巴哥"}},
+	{Alpha2: "TV", Alpha3: "TUV", Numeric: "798", Calling: "688", Names: map[string]string{"de": "Tuvalu", "en": "Tuvalu", "es": "Tuvalu", "fr": "Tuvalu", "it": "Tuvalu", "ja": "ツバル", "ko": "투발루", "pt": "Tuvalu", "ru": "Тувалу", "zh": "图瓦卢"}},
+	{Alpha2: "TW", Alpha3: "TWN", Numeric: "158", Calling: "886", Names: map[string]string{"de": "Taiwan", "en": "Taiwan", "es": "Taiwán", "fr": "Taïwan", "it": "Taiwan", "ja": "台湾", "ko": "대만", "pt": "Taiwan", "ru": "Тайвань", "zh": "台湾"}, Aliases: []string{"Republic of China"}},
+	{Alpha2: "TZ", Alpha3: "TZA", Numeric: "834", Calling: "255", Names: map[string]string{"de": "Tansania", "en": "Tanzania", "es": "Tanzania", "fr": "Tanzanie", "it": "Tanzania", "ja": "タンザニア", "ko": "탄자니아", "pt": "Tanzânia", "ru": "Танзания", "zh": "坦桑尼亚"}, Aliases: []string{"United Republic of Tanzania"}},
+	{Alpha2: "UA", Alpha3: "UKR", Numeric: "804", Calling: "380", Names: map[string]string{"de": "Ukraine", "en": "Ukraine", "es": "Ucrania", "fr": "Ukraine", "it": "Ucraina", "ja": "ウクライナ", "ko": "우크라이나", "pt": "Ucrânia", "ru": "Украина", "zh": "乌克兰"}},
+	{Alpha2: "UG", Alpha3: "UGA", Numeric: "800", Calling: "256", Names: map[string]string{"de": "Uganda", "en": "Uganda", "es": "Uganda", "fr": "Ouganda", "it": "Uganda", "ja": "ウガンダ", "ko": "우간다", "pt": "Uganda", "ru": "Уганда", "zh": "乌干达"}},
+	{Alpha2: "UM", Alpha3: "UMI", Numeric: "581", Calling: "1", Names: map[string]string{"de": "Amerikanische Überseeinseln", "en": "U.S. Outlying Islands", "es": "Islas menores alejadas de EE. UU.", "fr": "Îles mineures éloignées des États-Unis", "it": "Altre isole americane del Pacifico", "ja": "合衆国領有小離島", "ko": "미국령 해외 제도", "pt": "Ilhas Menores Distantes dos EUA", "ru": "Внешние малые о-ва (США)", "zh": "美国本土外小岛屿"}},
+	{Alpha2: "US", Alpha3: "USA", Numeric: "840", Calling: "1", Names: map[string]string{"de": "Vereinigte Staaten", "en": "United States", "es": "Estados Unidos", "fr": "États-Unis", "it": "Stati Uniti", "ja": "アメリカ合衆国", "ko": "미국", "pt": "Estados Unidos", "ru": "Соединенные Штаты", "zh": "美国"}, Aliases: []string{"USA", "United States of America", "America"}},
+	{Alpha2: "UY", Alpha3: "URY", Numeric: "858", Calling: "598", Names: map[string]string{"de": "Uruguay", "en": "Uruguay", "es": "Uruguay", "fr": "Uruguay", "it": "Uruguay", "ja": "ウルグアイ", "ko": "우루과이", "pt": "Uruguai", "ru": "Уругвай", "zh": "乌拉圭"}},
+	{Alpha2: "UZ", Alpha3: "UZB", Numeric: "860", Calling: "998", Names: map[string]string{"de": "Usbekistan", "en": "Uzbekistan", "es": "Uzbekistán", "fr": "Ouzbékistan", "it": "Uzbekistan", "ja": "ウズベキスタン", "ko": "우즈베키스탄", "pt": "Uzbequistão", "ru": "Узбекистан", "zh": "乌兹别克斯坦"}},
+	{Alpha2: "VA", Alpha3: "VAT", Numeric: "336", Calling: "39", Names: map[string]string{"de": "Vatikanstadt", "en": "Vatican City", "es": "Ciudad del Vaticano", "fr": "État de la Cité du Vatican", "it": "Città del Vaticano", "ja": "バチカン市国", "ko": "바티칸 시국", "pt": "Cidade do Vaticano", "ru": "Ватикан", "zh": "梵蒂冈"}, Aliases: []string{"Vatican", "Holy See"}},
+	{Alpha2: "VC", Alpha3: "VCT", Numeric: "670", Calling: "1", Names: map[string]string{"de": "St. Vincent und die Grenadinen", "en": "St. Vincent & Grenadines", "es": "San Vicente y las Granadinas", "fr": "Saint-Vincent-et-les-Grenadines", "it": "Saint Vincent e Grenadine", "ja": "セントビンセント及びグレナディーン諸島", "ko": "세인트빈센트그레나딘", "pt": "São Vicente e Granadinas", "ru": "Сент-Винсент и Гренадины", "zh": "圣文森特和格林纳丁斯"}},
+	{Alpha2: "VE", Alpha3: "VEN", Numeric: "862", Calling: "58", Names: map[string]string{"de": "Venezuela", "en": "Venezuela", "es": "Venezuela", "fr": "Venezuela", "it": "Venezuela", "ja": "ベネズエラ", "ko": "베네수엘라", "pt": "Venezuela", "ru": "Венесуэла", "zh": "委内瑞拉"}, Aliases: []string{"Bolivarian Republic of Venezuela"}},
+	{Alpha2: "VG", Alpha3: "VGB", Numeric: "092", Calling: "1", Names: map[string]string{"de": "Britische Jungferninseln", "en": "British Virgin Islands", "es": "Islas Vírgenes Británicas", "fr": "Îles Vierges britanniques", "it": "Isole Vergini Britanniche", "ja": "英領ヴァージン諸島", "ko": "영국령 버진아일랜드", "pt": "Ilhas Virgens Britânicas", "ru": "Виргинские о-ва (Британские)", "zh": "英属维尔京群岛"}},
+	{Alpha2: "VI", Alpha3: "VIR", Numeric: "850", Calling: "1", Names: map[string]string{"de": "Amerikanische Jungferninseln", "en": "U.S. Virgin Islands", "es": "Islas Vírgenes de EE. UU.", "fr": "Îles Vierges des États-Unis", "it": "Isole Vergini Americane", "ja": "米領ヴァージン諸島", "ko": "미국령 버진아일랜드", "pt": "Ilhas Virgens Americanas", "ru": "Виргинские о-ва (США)", "zh": "美属维尔京群岛"}},
+	{Alpha2: "VN", Alpha3: "VNM", Numeric: "704", Calling: "84", Names: map[string]string{"de": "Vietnam", "en": "Vietnam", "es": "Vietnam", "fr": "Vietnam", "it": "Vietnam", "ja": "ベトナム", "ko": "베트남", "pt": "Vietnã", "ru": "Вьетнам", "zh": "越南"}, Aliases: []string{"Viet Nam"}},
+	{Alpha2: "VU", Alpha3: "VUT", Numeric: "548", Calling: "678", Names: map[string]string{"de": "Vanuatu", "en": "Vanuatu", "es": "Vanuatu", "fr": "Vanuatu", "it": "Vanuatu", "ja": "バヌアツ", "ko": "바누아투", "pt": "Vanuatu", "ru": "Вануату", "zh": "瓦努阿图"}},
+	{Alpha2: "WF", Alpha3: "WLF", Numeric: "876", Calling: "681", Names: map[string]string{"de": "Wallis und Futuna", "en": "Wallis & Futuna", "es": "Wallis y Futuna", "fr": "Wallis-et-Futuna", "it": "Wallis e Futuna", "ja": "ウォリス・フツナ", "ko": "왈리스-푸투나 제도", "pt": "Wallis e Futuna", "ru": "Уоллис и Футуна", "zh": "瓦利斯和富图纳"}},
+	{Alpha2: "WS", Alpha3: "WSM", Numeric: "882", Calling: "685", Names: map[string]string{"de": "Samoa", "en": "Samoa", "es": "Samoa", "fr": "Samoa", "it": "Samoa", "ja": "サモア", "ko": "사모아", "pt": "Samoa", "ru": "Самоа", "zh": "萨摩亚"}},
+	{Alpha2: "YE", Alpha3: "YEM", Numeric: "887", Calling: "967", Names: map[string]string{"de": "Jemen", "en": "Yemen", "es": "Yemen", "fr": "Yémen", "it": "Yemen", "ja": "イエメン", "ko": "예멘", "pt": "Iêmen", "ru": "Йемен", "zh": "也门"}},
+	{Alpha2: "YT", Alpha3: "MYT", Numeric: "175", Calling: "262", Names: map[string]string{"de": "Mayotte", "en": "Mayotte", "es": "Mayotte", "fr": "Mayotte", "it": "Mayotte", "ja": "マヨット", "ko": "마요트", "pt": "Mayotte", "ru": "Майотта", "zh": "马约特"}},
+	{Alpha2: "ZA", Alpha3: "ZAF", Numeric: "710", Calling: "27", Names: map[string]string{"de": "Südafrika", "en": "South Africa", "es": "Sudáfrica", "fr": "Afrique du Sud", "it": "Sudafrica", "ja": "南アフリカ", "ko": "남아프리카", "pt": "África do Sul", "ru": "Южно-Африканская Республика", "zh": "南非"}},
+	{Alpha2: "ZM", Alpha3: "ZMB", Numeric: "894", Calling: "260", Names: map[string]string{"de": "Sambia", "en": "Zambia", "es": "Zambia", "fr": "Zambie", "it": "Zambia", "ja": "ザンビア", "ko": "잠비아", "pt": "Zâmbia", "ru": "Замбия", "zh": "赞比亚"}},
+	{Alpha2: "ZW", Alpha3: "ZWE", Numeric: "716", Calling: "263", Names: map[string]string{"de": "Simbabwe", "en": "Zimbabwe", "es": "Zimbabue", "fr": "Zimbabwe", "it": "Zimbabwe", "ja": "ジンバブエ", "ko": "짐바브웨", "pt": "Zimbábue", "ru": "Зимбабве", "zh": "津巴布韦"}},
+}
@@ -0,0 +1,176 @@
+//go:build ignore
+// +build ignore
+
+// gen_country writes country_data.go, the ISO 3166-1 table embedded in Vibe.
+// Codes and localized names come from the CLDR data of golang.org/x/text,
+// calling codes and aliases are kept below.
+//
+//	go run gen_country.go
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+)
+
+// countryLangs are the languages country names are embedded in.
+var countryLangs = []string{"de", "en", "es", "fr", "it", "ja", "ko", "pt", "ru", "zh"}
+
+// callingCodes lists every officially assigned ISO 3166-1 alpha-2 code with
+// its ITU-T E.164 country calling code.
+var callingCodes = map[string]string{
+	"AD": "376", "AE": "971", "AF": "93", "AG": "1", "AI": "1", "AL": "355", "AM": "374", "AO": "244",
+	"AQ": "672", "AR": "54", "AS": "1", "AT": "43", "AU": "61", "AW": "297", "AX": "358", "AZ": "994",
+	"BA": "387", "BB": "1", "BD": "880", "BE": "32", "BF": "226", "BG": "359", "BH": "973", "BI": "257",
+	"BJ": "229", "BL": "590", "BM": "1", "BN": "673", "BO": "591", "BQ": "599", "BR": "55", "BS": "1",
+	"BT": "975", "BV": "47", "BW": "267", "BY": "375", "BZ": "501",
+	"CA": "1", "CC": "61", "CD": "243", "CF": "236", "CG": "242", "CH": "41", "CI": "225", "CK": "682",
+	"CL": "56", "CM": "237", "CN": "86", "CO": "57", "CR": "506", "CU": "53", "CV": "238", "CW": "599",
+	"CX": "61", "CY": "357", "CZ": "420",
+	"DE": "49", "DJ": "253", "DK": "45", "DM": "1", "DO": "1", "DZ": "213",
+	"EC": "593", "EE": "372", "EG": "20", "EH": "212", "ER": "291", "ES": "34", "ET": "251",
+	"FI": "358", "FJ": "679", "FK": "500", "FM": "691", "FO": "298", "FR": "33",
+	"GA": "241", "GB": "44", "GD": "1", "GE": "995", "GF": "594", "GG": "44", "GH": "233", "GI": "350",
+	"GL": "299", "GM": "220", "GN": "224", "GP": "590", "GQ": "240", "GR": "30", "GS": "500", "GT": "502",
+	"GU": "1", "GW": "245", "GY": "592",
+	"HK": "852", "HM": "672", "HN": "504", "HR": "385", "HT": "509", "HU": "36",
+	"ID": "62", "IE": "353", "IL": "972", "IM": "44", "IN": "91", "IO": "246", "IQ": "964", "IR": "98",
+	"IS": "354", "IT": "39",
+	"JE": "44", "JM": "1", "JO": "962", "JP": "81",
+	"KE": "254", "KG": "996", "KH": "855", "KI": "686", "KM": "269", "KN": "1", "KP": "850", "KR": "82",
+	"KW": "965", "KY": "1", "KZ": "7",
+	"LA": "856", "LB": "961", "LC": "1", "LI": "423", "LK": "94", "LR": "231", "LS": "266", "LT": "370",
+	"LU": "352", "LV": "371", "LY": "218",
+	"MA": "212", "MC": "377", "MD": "373", "ME": "382", "MF": "590", "MG": "261", "MH": "692", "MK": "389",
+	"ML": "223", "MM": "95", "MN": "976", "MO": "853", "MP": "1", "MQ": "596", "MR": "222", "MS": "1",
+	"MT": "356", "MU": "230", "MV": "960", "MW": "265", "MX": "52", "MY": "60", "MZ": "258",
+	"NA": "264", "NC": "687", "NE": "227", "NF": "672", "NG": "234", "NI": "505", "NL": "31", "NO": "47",
+	"NP": "977", "NR": "674", "NU": "683", "NZ": "64",
+	"OM": "968",
+	"PA": "507", "PE": "51", "PF": "689", "PG": "675", "PH": "63", "PK": "92", "PL": "48", "PM": "508",
+	"PN": "64", "PR": "1", "PS": "970", "PT": "351", "PW": "680", "PY": "595",
+	"QA": "974",
+	"RE": "262", "RO": "40", "RS": "381", "RU": "7", "RW": "250",
+	"SA": "966", "SB": "677", "SC": "248", "SD": "249", "SE": "46", "SG": "65", "SH": "290", "SI": "386",
+	"SJ": "47", "SK": "421", "SL": "232", "SM": "378", "SN": "221", "SO": "252", "SR": "597", "SS": "211",
+	"ST": "239", "SV": "503", "SX": "1", "SY": "963", "SZ": "268",
+	"TC": "1", "TD": "235", "TF": "262", "TG": "228", "TH": "66", "TJ": "992", "TK": "690", "TL": "670",
+	"TM": "993", "TN": "216", "TO": "676", "TR": "90", "TT": "1", "TV": "688", "TW": "886", "TZ": "255",
+	"UA": "380", "UG": "256", "UM": "1", "US": "1", "UY": "598", "UZ": "998",
+	"VA": "39", "VC": "1", "VE": "58", "VG": "1", "VI": "1", "VN": "84", "VU": "678",
+	"WF": "681", "WS": "685",
+	"YE": "967", "YT": "262",
+	"ZA": "27", "ZM": "260", "ZW": "263",
+}
+
+// englishNames replaces CLDR names older than the current ISO short names.
+var englishNames = map[string]string{
+	"MK": "North Macedonia",
+	"SZ": "Eswatini",
+	"TR": "Türkiye",
+}
+
+// aliases are other names people commonly type for a country.
+var aliases = map[string][]string{
+	"BA": {"Bosnia", "Bosnia and Herzegovina"},
+	"BO": {"Plurinational State of Bolivia"},
+	"CD": {"DR Congo", "DRC", "Democratic Republic of the Congo"},
+	"CG": {"Republic of the Congo"},
+	"CI": {"Ivory Coast"},
+	"CV": {"Cabo Verde"},
+	"CZ": {"Czech Republic"},
+	"GB": {"UK", "Great Britain", "Britain", "England", "Scotland", "Wales", "Northern Ireland"},
+	"HK": {"Hong Kong"},
+	"IR": {"Islamic Republic of Iran"},
+	"KP": {"DPRK", "Democratic People's Republic of Korea"},
+	"KR": {"Korea", "Republic of Korea"},
+	"LA": {"Lao People's Democratic Republic"},
+	"MD": {"Republic of Moldova"},
+	"MK": {"Macedonia"},
+	"MM": {"Burma"},
+	"MO": {"Macau", "Macao"},
+	"NL": {"Holland"},
+	"PS": {"Palestine"},
+	"RU": {"Russian Federation"},
+	"SY": {"Syrian Arab Republic"},
+	"SZ": {"Swaziland"},
+	"TL": {"East Timor"},
+	"TR": {"Turkey"},
+	"TW": {"Republic of China"},
+	"TZ": {"United Republic of Tanzania"},
+	"US": {"USA", "United States of America", "America"},
+	"VA": {"Vatican", "Holy See"},
+	"VE": {"Bolivarian Republic of Venezuela"},
+	"VN": {"Viet Nam"},
+}
+
+func main() {
+	codes := []string{}
+	for code := range callingCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	namers := map[string]display.Namer{}
+	for _, lang := range countryLangs {
+		namers[lang] = display.Regions(language.MustParse(lang))
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintln(buf, "// Code generated by gen_country.go. DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package utils")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "var countries = []CountryInfo{")
+	for _, code := range codes {
+		r := language.MustParseRegion(code)
+		if r.ISO3() == "" || r.ISO3() == "ZZZ" {
+			log.Fatalf("%s: no alpha-3 code", code)
+		}
+		numeric := r.M49()
+		if numeric == 0 {
+			log.Fatalf("%s: no numeric code", code)
+		}
+
+		fmt.Fprintf(buf, "\t{Alpha2: %q, Alpha3: %q, Numeric: %q, Calling: %q, Names: map[string]string{",
+			code, r.ISO3(), fmt.Sprintf("%03d", numeric), callingCodes[code])
+		for i, lang := range countryLangs {
+			name := namers[lang].Name(r)
+			if lang == "en" && englishNames[code] != "" {
+				name = englishNames[code]
+			}
+			if name == "" {
+				log.Fatalf("%s: no %s name", code, lang)
+			}
+			if i > 0 {
+				fmt.Fprint(buf, ", ")
+			}
+			fmt.Fprintf(buf, "%q: %q", lang, name)
+		}
+		fmt.Fprint(buf, "}")
+		if len(aliases[code]) > 0 {
+			quoted := []string{}
+			for _, alias := range aliases[code] {
+				quoted = append(quoted, fmt.Sprintf("%q", alias))
+			}
+			fmt.Fprintf(buf, ", Aliases: []string{%s}", strings.Join(quoted, ", "))
+		}
+		fmt.Fprintln(buf, "},")
+	}
+	fmt.Fprintln(buf, "}")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("country_data.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
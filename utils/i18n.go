@@ -0,0 +1,215 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"golang.org/x/text/language"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Catalog holds user-facing texts by language and key. Texts are Go
+// templates, so parameters are written like {{.Name}}. Languages missing a
+// key fall back to the base language, then to Default.
+type Catalog struct {
+	Default string // "en" when empty
+
+	mu        sync.RWMutex
+	messages  map[string]map[string]string
+	templates map[string]*template.Template
+}
+
+func (c *Catalog) defaultLang() string {
+	if c.Default == "" {
+		return "en"
+	}
+	return canonicalLang(c.Default)
+}
+
+func canonicalLang(lang string) string {
+	tag, err := language.Parse(strings.Replace(lang, "_", "-", -1))
+	if err != nil {
+		return strings.ToLower(lang)
+	}
+	return tag.String()
+}
+
+// Add merges texts into a language, replacing those with the same key.
+func (c *Catalog) Add(lang string, messages map[string]string) {
+	lang = canonicalLang(lang)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.messages == nil {
+		c.messages = map[string]map[string]string{}
+	}
+	if c.messages[lang] == nil {
+		c.messages[lang] = map[string]string{}
+	}
+	for key, text := range messages {
+		c.messages[lang][key] = text
+	}
+	c.templates = nil
+}
+
+// LoadDir adds every <language>.json file of dir, such as fr.json or
+// pt-BR.json, each a flat object of texts by key.
+func (c *Catalog) LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		messages := map[string]string{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return errors.New("BAD_LOCALE_FILE: " + filepath.Base(file))
+		}
+		c.Add(strings.TrimSuffix(filepath.Base(file), ".json"), messages)
+	}
+	return nil
+}
+
+// Languages returns the languages of the catalog, the default first.
+func (c *Catalog) Languages() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	def := c.defaultLang()
+	langs := []string{}
+	for lang := range c.messages {
+		if lang != def {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+	return append([]string{def}, langs...)
+}
+
+// Match picks the catalog language closest to the preferences, tried in
+// order. Each one is a language tag or an Accept-Language header value.
+func (c *Catalog) Match(prefs ...string) string {
+	langs := c.Languages()
+	tags := []language.Tag{}
+	for _, lang := range langs {
+		tags = append(tags, language.Make(lang))
+	}
+	matcher := language.NewMatcher(tags)
+
+	for _, pref := range prefs {
+		if strings.TrimSpace(pref) == "" {
+			continue
+		}
+		wanted, _, err := language.ParseAcceptLanguage(pref)
+		if err != nil || len(wanted) == 0 {
+			continue
+		}
+		if _, i, confidence := matcher.Match(wanted...); confidence != language.No {
+			return langs[i]
+		}
+	}
+	return langs[0]
+}
+
+// Render executes the text of key in lang with data.
+func (c *Catalog) Render(lang, key string, data interface{}) (string, error) {
+	t, err := c.template(canonicalLang(lang), key)
+	if err != nil {
+		return "", err
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	buf := new(bytes.Buffer)
+	if err := t.Execute(buf, data); err != nil {
+		return "", err
+	}
+	// Missing parameters of a map print as "<no value>" whatever the
+	// missingkey option, they are left empty instead.
+	return strings.Replace(buf.String(), "<no value>", "", -1), nil
+}
+
+// Text is Render for messages, it returns the key itself when there is no
+// text for it.
+func (c *Catalog) Text(lang, key string, data interface{}) string {
+	text, err := c.Render(lang, key, data)
+	if err != nil {
+		return key
+	}
+	return text
+}
+
+// Has tells if key has a text in the default language.
+func (c *Catalog) Has(key string) bool {
+	_, _, ok := c.lookup(c.defaultLang(), key)
+	return ok
+}
+
+// Missing lists the keys of the default language lang has no text for.
+func (c *Catalog) Missing(lang string) []string {
+	lang = canonicalLang(lang)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	missing := []string{}
+	for key := range c.messages[c.defaultLang()] {
+		if _, ok := c.messages[lang][key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// lookup finds the text of key for lang, its base language or the default.
+func (c *Catalog) lookup(lang, key string) (string, string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	candidates := []string{lang}
+	if base, _ := language.Make(lang).Base(); base.String() != lang {
+		candidates = append(candidates, base.String())
+	}
+	candidates = append(candidates, c.defaultLang())
+	for _, l := range candidates {
+		if text, ok := c.messages[l][key]; ok {
+			return l, text, true
+		}
+	}
+	return "", "", false
+}
+
+func (c *Catalog) template(lang, key string) (*template.Template, error) {
+	found, text, ok := c.lookup(lang, key)
+	if !ok {
+		return nil, errors.New("MESSAGE_NOT_FOUND")
+	}
+
+	name := found + "\x00" + key
+	c.mu.RLock()
+	t := c.templates[name]
+	c.mu.RUnlock()
+	if t != nil {
+		return t, nil
+	}
+
+	t, err := template.New(key).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if c.templates == nil {
+		c.templates = map[string]*template.Template{}
+	}
+	c.templates[name] = t
+	c.mu.Unlock()
+	return t, nil
+}
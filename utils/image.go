@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"golang.org/x/image/draw"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// Imager validates uploaded pictures and renders the square variants served
+// as avatars. Every output is re-encoded, so no metadata such as EXIF from
+// the upload survives.
+type Imager struct {
+	MaxBytes  int64
+	MaxPixels int
+}
+
+var imageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Decode checks the size and type of an upload and decodes it. JPEG pictures
+// are turned upright according to their EXIF orientation.
+func (m *Imager) Decode(data []byte) (image.Image, error) {
+	if m.MaxBytes > 0 && int64(len(data)) > m.MaxBytes {
+		return nil, errors.New("IMAGE_TOO_LARGE")
+	}
+	ct := http.DetectContentType(data)
+	if !imageTypes[ct] {
+		return nil, errors.New("UNSUPPORTED_IMAGE")
+	}
+
+	// Refuse decompression bombs before allocating the pixels.
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("BAD_IMAGE")
+	}
+	if m.MaxPixels > 0 && cfg.Width*cfg.Height > m.MaxPixels {
+		return nil, errors.New("IMAGE_TOO_LARGE")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("BAD_IMAGE")
+	}
+	if ct == "image/jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	return img, nil
+}
+
+// Square crops the center of img and scales it to size by size pixels, on a
+// white background for transparent pictures.
+func (m *Imager) Square(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	x, y := b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2
+	crop := image.Rect(x, y, x+side, y+side)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Over, nil)
+	return dst
+}
+
+func (m *Imager) JPEG(img image.Image) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 90})
+	return buf.Bytes(), err
+}
+
+func (m *Imager) PNG(img image.Image) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := png.Encode(buf, img)
+	return buf.Bytes(), err
+}
+
+// Identicon draws a symmetric 5x5 pattern derived from seed, so every user
+// gets a stable picture without uploading one.
+func (m *Imager) Identicon(seed string, size int) image.Image {
+	sum := sha256.Sum256([]byte(seed))
+	fg := color.RGBA{sum[0]/2 + 64, sum[1]/2 + 64, sum[2]/2 + 64, 255}
+	bg := color.RGBA{240, 240, 240, 255}
+
+	const cells = 5
+	cell := size / (cells + 1)
+	margin := (size - cell*cells) / 2
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	for row := 0; row < cells; row++ {
+		for col := 0; col < (cells+1)/2; col++ {
+			if sum[3+row*3+col]%2 == 0 {
+				continue
+			}
+			for _, c := range []int{col, cells - 1 - col} {
+				r := image.Rect(margin+c*cell, margin+row*cell, margin+(c+1)*cell, margin+(row+1)*cell)
+				draw.Draw(dst, r, image.NewUniform(fg), image.Point{}, draw.Src)
+			}
+		}
+	}
+	return dst
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG file, 1 when it has
+// none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xDA || length < 2 || i+2+length > len(data) {
+			return 1 // image data starts, no more metadata
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for e := 0; e < entries; e++ {
+		at := ifd + 2 + e*12
+		if at+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[at:]) == 0x0112 {
+			if o := int(order.Uint16(tiff[at+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// orient applies an EXIF orientation, 2 to 8 flip and rotate the picture.
+func orient(img image.Image, o int) image.Image {
+	if o < 2 || o > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if o >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch o {
+			case 2: // mirrored
+				sx, sy = w-1-x, y
+			case 3: // upside down
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored upside down
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // rotated 90 degrees clockwise
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotated 90 degrees counterclockwise
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, img.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+	return dst
+}
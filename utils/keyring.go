@@ -0,0 +1,224 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+)
+
+const sealedPrefix = "enc:v1:"
+
+// Keyring encrypts values with AES-GCM envelopes. Every value gets its own
+// random data key, which is wrapped with the primary key encryption key of
+// the ring. A sealed value reads
+//
+//	enc:v1:<key id>:<wrapped data key>:<ciphertext>
+//
+// so older keys stay usable for reading and rotation only rewraps data keys.
+type Keyring struct {
+	Keys     map[string][]byte // key encryption keys by id, 32 bytes each
+	Primary  string            // id of the key used to seal new values
+	IndexKey []byte            // HMAC key of blind indexes
+}
+
+// Check reports whether the ring can seal values.
+func (k *Keyring) Check() error {
+	if k.Primary == "" || strings.Contains(k.Primary, ":") {
+		return errors.New("KEYRING_NO_PRIMARY")
+	}
+	if _, ok := k.Keys[k.Primary]; !ok {
+		return errors.New("KEYRING_NO_PRIMARY")
+	}
+	for _, key := range k.Keys {
+		if len(key) != 32 {
+			return errors.New("KEYRING_BAD_KEY")
+		}
+	}
+	if len(k.IndexKey) < 16 {
+		return errors.New("KEYRING_BAD_INDEX_KEY")
+	}
+	return nil
+}
+
+// Seal encrypts plaintext under the primary key. The additional data, such as
+// the one of FieldAD, must be given again to open the value.
+func (k *Keyring) Seal(plaintext, additional []byte) (string, error) {
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return "", err
+	}
+	ciphertext, err := gcmSeal(dek, plaintext, additional)
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := k.wrap(k.Primary, dek)
+	if err != nil {
+		return "", err
+	}
+	return sealedPrefix + k.Primary + ":" + wrapped + ":" + encode(ciphertext), nil
+}
+
+// Open decrypts a value returned by Seal.
+func (k *Keyring) Open(sealed string, additional []byte) ([]byte, error) {
+	kid, wrapped, ciphertext, err := splitSealed(sealed)
+	if err != nil {
+		return nil, err
+	}
+	dek, err := k.unwrap(kid, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := decode(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	return gcmOpen(dek, raw, additional)
+}
+
+// Rewrap wraps the data key of a sealed value with the primary key. The
+// ciphertext itself is unchanged.
+func (k *Keyring) Rewrap(sealed string) (string, error) {
+	kid, wrapped, ciphertext, err := splitSealed(sealed)
+	if err != nil {
+		return "", err
+	}
+	if kid == k.Primary {
+		return sealed, nil
+	}
+	dek, err := k.unwrap(kid, wrapped)
+	if err != nil {
+		return "", err
+	}
+	if wrapped, err = k.wrap(k.Primary, dek); err != nil {
+		return "", err
+	}
+	return sealedPrefix + k.Primary + ":" + wrapped + ":" + ciphertext, nil
+}
+
+// FieldAD is the additional data of a value stored in field of the document
+// id of collection. A value sealed with it cannot be copied to another field
+// or document and still open.
+func FieldAD(collection, id, field string) []byte {
+	return []byte(collection + "/" + id + "/" + field)
+}
+
+// IsSealed reports whether a stored value was produced by Seal.
+func (k *Keyring) IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+// KeyID returns the id of the key wrapping a sealed value.
+func (k *Keyring) KeyID(sealed string) string {
+	kid, _, _, err := splitSealed(sealed)
+	if err != nil {
+		return ""
+	}
+	return kid
+}
+
+// BlindIndex returns a keyed hash of value for exact-match lookups of sealed
+// fields.
+func (k *Keyring) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, k.IndexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateKey returns a random key encoded as the config expects it.
+func (k *Keyring) GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func (k *Keyring) wrap(kid string, dek []byte) (string, error) {
+	kek, ok := k.Keys[kid]
+	if !ok {
+		return "", errors.New("KEYRING_UNKNOWN_KEY")
+	}
+	wrapped, err := gcmSeal(kek, dek, []byte(kid))
+	if err != nil {
+		return "", err
+	}
+	return encode(wrapped), nil
+}
+
+func (k *Keyring) unwrap(kid, wrapped string) ([]byte, error) {
+	kek, ok := k.Keys[kid]
+	if !ok {
+		return nil, errors.New("KEYRING_UNKNOWN_KEY")
+	}
+	raw, err := decode(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return gcmOpen(kek, raw, []byte(kid))
+}
+
+func splitSealed(sealed string) (kid, wrapped, ciphertext string, err error) {
+	if !strings.HasPrefix(sealed, sealedPrefix) {
+		return "", "", "", errors.New("NOT_SEALED")
+	}
+	parts := strings.Split(sealed[len(sealedPrefix):], ":")
+	if len(parts) != 3 {
+		return "", "", "", errors.New("NOT_SEALED")
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// gcmSeal returns the nonce followed by the ciphertext.
+func gcmSeal(key, plaintext, additional []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+func gcmOpen(key, sealed, additional []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("DECRYPT_FAILED")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], additional)
+	if err != nil {
+		return nil, errors.New("DECRYPT_FAILED")
+	}
+	return plaintext, nil
+}
+
+func encode(b []byte) string {
+	return base64.RawStdEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	b, err := base64.RawStdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("NOT_SEALED")
+	}
+	return b, nil
+}
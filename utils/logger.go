@@ -15,11 +15,8 @@ type (
 	}
 )
 
-var (
-	conf = models.Config{}.Init()
-)
-
 func (l *Logger) Init() {
+	conf := models.Conf()
 	if l.Log == nil {
 		l.Log = logrus.New()
 
@@ -29,18 +26,6 @@ func (l *Logger) Init() {
 		// Output to stderr instead of stdout, could also be a file.
 		// l.Log.Out = os.Stderr
 
-		// Only log the warning severity or above.
-		switch strings.ToLower(conf.Logger.Level) {
-		case "debug":
-			l.Log.Level = logrus.DebugLevel
-		case "info":
-			l.Log.Level = logrus.InfoLevel
-		case "warn":
-			l.Log.Level = logrus.WarnLevel
-		default:
-			l.Log.Level = logrus.ErrorLevel
-		}
-
 		// Create log directory if not exist
 		logDir := strings.Replace(conf.Logger.Error,
 			"/"+strings.Split(conf.Logger.Error, "/")[len(strings.Split(conf.Logger.Error, "/"))-1:][0],
@@ -58,6 +43,18 @@ func (l *Logger) Init() {
 			logrus.ErrorLevel: conf.Logger.Error,
 		}))
 	}
+
+	// Only log the configured severity or above, followed on reload.
+	switch strings.ToLower(conf.Logger.Level) {
+	case "debug":
+		l.Log.Level = logrus.DebugLevel
+	case "info":
+		l.Log.Level = logrus.InfoLevel
+	case "warn":
+		l.Log.Level = logrus.WarnLevel
+	default:
+		l.Log.Level = logrus.ErrorLevel
+	}
 }
 
 func (l *Logger) Debug(fields map[string]interface{}, errString string) {
@@ -0,0 +1,282 @@
+package utils
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPI builds an OpenAPI 3 document from operations and the Go types of
+// their bodies. Named struct types become components of the same, capitalized,
+// name.
+type OpenAPI struct {
+	Title       string
+	Version     string
+	Description string
+	// Problem is a sample of the body of error answers, served as ProblemType
+	// by the default response of every operation when set.
+	Problem     interface{}
+	ProblemType string
+
+	operations []Operation
+}
+
+// Operation is one method on one path, with the :name parameters of Echo.
+// Request and Response are samples of the bodies, nil for none.
+type Operation struct {
+	Method   string
+	Path     string
+	Summary  string
+	Tag      string
+	Secured  bool    //requires a bearer token
+	Params   []Param //query and header parameters, path ones are found in Path
+	Request  interface{}
+	Consumes []string //content types of the request, JSON when empty
+	Response interface{}
+	Produces string //content type of the response, JSON when empty
+	Status   int    //success status, 200 when zero
+}
+
+// Param is a query or header parameter.
+type Param struct {
+	Name     string
+	In       string //query when empty, or header
+	Type     string //string when empty, integer or boolean
+	Format   string //such as date-time
+	Required bool
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Add appends operations to the document, in the order they are listed.
+func (o *OpenAPI) Add(ops ...Operation) {
+	o.operations = append(o.operations, ops...)
+}
+
+// Document returns the OpenAPI document, to be encoded as JSON.
+func (o *OpenAPI) Document() map[string]interface{} {
+	schemas := map[string]interface{}{}
+	paths := map[string]interface{}{}
+	for _, op := range o.operations {
+		path, params := openAPIPath(op.Path)
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[path] = item
+		}
+		item[strings.ToLower(op.Method)] = o.operation(op, params, schemas)
+	}
+
+	components := map[string]interface{}{
+		"schemas": schemas,
+		"securitySchemes": map[string]interface{}{
+			"bearer": map[string]interface{}{
+				"type":         "http",
+				"scheme":       "bearer",
+				"bearerFormat": "JWT",
+			},
+		},
+	}
+	if o.Problem != nil {
+		components["responses"] = map[string]interface{}{
+			"Problem": map[string]interface{}{
+				"description": "Problem",
+				"content":     content([]string{o.ProblemType}, schema(reflect.TypeOf(o.Problem), schemas)),
+			},
+		}
+	}
+
+	info := map[string]interface{}{
+		"title":   o.Title,
+		"version": o.Version,
+	}
+	if o.Description != "" {
+		info["description"] = o.Description
+	}
+	return map[string]interface{}{
+		"openapi":    "3.0.3",
+		"info":       info,
+		"paths":      paths,
+		"components": components,
+	}
+}
+
+func (o *OpenAPI) operation(op Operation, params []interface{}, schemas map[string]interface{}) map[string]interface{} {
+	for _, p := range op.Params {
+		in := p.In
+		if in == "" {
+			in = "query"
+		}
+		params = append(params, map[string]interface{}{
+			"name":     p.Name,
+			"in":       in,
+			"required": p.Required,
+			"schema":   paramSchema(p.Type, p.Format),
+		})
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	res := map[string]interface{}{
+		"description": http.StatusText(status),
+	}
+	switch {
+	case op.Response != nil:
+		res["content"] = content([]string{op.Produces}, schema(reflect.TypeOf(op.Response), schemas))
+	case op.Produces != "":
+		s := paramSchema("string", "binary")
+		if strings.HasPrefix(op.Produces, "text/") {
+			s = paramSchema("string", "")
+		}
+		res["content"] = content([]string{op.Produces}, s)
+	}
+	responses := map[string]interface{}{
+		strconv.Itoa(status): res,
+	}
+	if o.Problem != nil {
+		responses["default"] = map[string]interface{}{"$ref": "#/components/responses/Problem"}
+	}
+
+	m := map[string]interface{}{
+		"summary":   op.Summary,
+		"responses": responses,
+	}
+	if op.Tag != "" {
+		m["tags"] = []string{op.Tag}
+	}
+	if len(params) > 0 {
+		m["parameters"] = params
+	}
+	if op.Request != nil {
+		m["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  content(op.Consumes, schema(reflect.TypeOf(op.Request), schemas)),
+		}
+	}
+	if op.Secured {
+		m["security"] = []interface{}{map[string]interface{}{"bearer": []string{}}}
+	}
+	return m
+}
+
+// openAPIPath turns the :name parameters of an Echo path into {name} ones.
+func openAPIPath(path string) (string, []interface{}) {
+	params := []interface{}{}
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			parts[i] = "{" + part[1:] + "}"
+			params = append(params, map[string]interface{}{
+				"name":     part[1:],
+				"in":       "path",
+				"required": true,
+				"schema":   paramSchema("string", ""),
+			})
+		}
+	}
+	if path == "" {
+		return "/", params
+	}
+	return strings.Join(parts, "/"), params
+}
+
+func content(types []string, s map[string]interface{}) map[string]interface{} {
+	c := map[string]interface{}{}
+	for _, t := range types {
+		if t == "" {
+			t = "application/json"
+		}
+		c[t] = map[string]interface{}{"schema": s}
+	}
+	if len(c) == 0 {
+		c["application/json"] = map[string]interface{}{"schema": s}
+	}
+	return c
+}
+
+func paramSchema(typ, format string) map[string]interface{} {
+	if typ == "" {
+		typ = "string"
+	}
+	s := map[string]interface{}{"type": typ}
+	if format != "" {
+		s["format"] = format
+	}
+	return s
+}
+
+// schema describes t, named structs are added to schemas and referenced.
+func schema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return paramSchema("string", "date-time")
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return paramSchema("string", "")
+	case reflect.Bool:
+		return paramSchema("boolean", "")
+	case reflect.Int64, reflect.Uint64:
+		return paramSchema("integer", "int64")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return paramSchema("integer", "")
+	case reflect.Float32, reflect.Float64:
+		return paramSchema("number", "")
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return paramSchema("string", "binary")
+		}
+		return map[string]interface{}{"type": "array", "items": schema(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schema(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return objectSchema(t, schemas)
+		}
+		name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+		if _, ok := schemas[name]; !ok {
+			// Taken before the fields are described, for recursive types.
+			schemas[name] = map[string]interface{}{}
+			schemas[name] = objectSchema(t, schemas)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+func objectSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	props := map[string]interface{}{}
+	addFields(t, props, schemas)
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+// addFields describes the JSON fields of t, those of embedded structs
+// included.
+func addFields(t reflect.Type, props, schemas map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			addFields(f.Type, props, schemas)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = schema(f.Type, schemas)
+	}
+}
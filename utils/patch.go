@@ -0,0 +1,341 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Patch applies JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902)
+// documents to decoded JSON values.
+type Patch struct{}
+
+type patchOp struct {
+	Op    string     `json:"op"`
+	Path  *string    `json:"path"`
+	From  *string    `json:"from"`
+	Value patchValue `json:"value"`
+}
+
+// patchValue is the value of an operation, telling an explicit null from an
+// absent member.
+type patchValue struct {
+	raw json.RawMessage
+	set bool
+}
+
+func (v *patchValue) UnmarshalJSON(b []byte) error {
+	v.raw, v.set = append(json.RawMessage{}, b...), true
+	return nil
+}
+
+// Merge applies a merge patch to a copy of target and returns it. Members set
+// to null in the patch are removed, objects are merged recursively and any
+// other value replaces the target value.
+func (p *Patch) Merge(target interface{}, patch []byte) (interface{}, error) {
+	var mp interface{}
+	if err := json.Unmarshal(patch, &mp); err != nil {
+		return nil, errors.New("BAD_PATCH")
+	}
+	return mergeValue(deepCopy(target), mp), nil
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+	pm, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	tm, ok := target.(map[string]interface{})
+	if !ok {
+		tm = map[string]interface{}{}
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(tm, k)
+		} else {
+			tm[k] = mergeValue(tm[k], v)
+		}
+	}
+	return tm
+}
+
+// Paths returns the JSON pointers touched by a JSON Patch document, "from"
+// pointers included, so callers can check them before applying it.
+func (p *Patch) Paths(patch []byte) ([]string, error) {
+	ops, err := parsePatchOps(patch)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{}
+	for _, op := range ops {
+		paths = append(paths, *op.Path)
+		if op.From != nil {
+			paths = append(paths, *op.From)
+		}
+	}
+	return paths, nil
+}
+
+// Apply runs the operations of a JSON Patch document in order on a copy of
+// target. The patch is atomic, target is left untouched when an operation
+// fails.
+func (p *Patch) Apply(target interface{}, patch []byte) (interface{}, error) {
+	ops, err := parsePatchOps(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := deepCopy(target)
+	for _, op := range ops {
+		var value interface{}
+		if op.Value.set {
+			if err := json.Unmarshal(op.Value.raw, &value); err != nil {
+				return nil, errors.New("BAD_PATCH")
+			}
+		}
+
+		switch op.Op {
+		case "add":
+			doc, err = pointerAdd(doc, *op.Path, value)
+		case "remove":
+			doc, _, err = pointerRemove(doc, *op.Path)
+		case "replace":
+			if _, err = pointerGet(doc, *op.Path); err == nil {
+				doc, _, err = pointerRemove(doc, *op.Path)
+			}
+			if err == nil {
+				doc, err = pointerAdd(doc, *op.Path, value)
+			}
+		case "move":
+			var v interface{}
+			if strings.HasPrefix(*op.Path, *op.From+"/") {
+				return nil, errors.New("BAD_PATCH")
+			}
+			if doc, v, err = pointerRemove(doc, *op.From); err == nil {
+				doc, err = pointerAdd(doc, *op.Path, v)
+			}
+		case "copy":
+			var v interface{}
+			if v, err = pointerGet(doc, *op.From); err == nil {
+				doc, err = pointerAdd(doc, *op.Path, deepCopy(v))
+			}
+		case "test":
+			var v interface{}
+			if v, err = pointerGet(doc, *op.Path); err == nil && !reflect.DeepEqual(v, value) {
+				err = errors.New("PATCH_TEST_FAILED")
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+func parsePatchOps(patch []byte) ([]patchOp, error) {
+	ops := []patchOp{}
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, errors.New("BAD_PATCH")
+	}
+	for _, op := range ops {
+		if op.Path == nil {
+			return nil, errors.New("BAD_PATCH")
+		}
+		switch op.Op {
+		case "add", "replace", "test":
+			if !op.Value.set {
+				return nil, errors.New("BAD_PATCH")
+			}
+		case "move", "copy":
+			if op.From == nil {
+				return nil, errors.New("BAD_PATCH")
+			}
+		case "remove":
+		default:
+			return nil, errors.New("BAD_PATCH")
+		}
+	}
+	return ops, nil
+}
+
+// splitPointer decodes a JSON Pointer (RFC 6901) into reference tokens.
+func splitPointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, errors.New("BAD_PATCH")
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(t, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, errors.New("PATH_NOT_FOUND")
+	}
+	if i > length || (!allowEnd && i == length) {
+		return 0, errors.New("PATH_NOT_FOUND")
+	}
+	return i, nil
+}
+
+func pointerGet(doc interface{}, ptr string) (interface{}, error) {
+	tokens, err := splitPointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	cur := doc
+	for _, t := range tokens {
+		switch node := cur.(type) {
+		case map[string]interface{}:
+			v, ok := node[t]
+			if !ok {
+				return nil, errors.New("PATH_NOT_FOUND")
+			}
+			cur = v
+		case []interface{}:
+			i, err := arrayIndex(t, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			cur = node[i]
+		default:
+			return nil, errors.New("PATH_NOT_FOUND")
+		}
+	}
+	return cur, nil
+}
+
+// pointerAdd returns doc with value added at ptr. Arrays are rebuilt, so the
+// parent of an array is updated with the new slice.
+func pointerAdd(doc interface{}, ptr string, value interface{}) (interface{}, error) {
+	tokens, err := splitPointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return addAt(doc, tokens, value)
+}
+
+func addAt(node interface{}, tokens []string, value interface{}) (interface{}, error) {
+	t := tokens[0]
+	last := len(tokens) == 1
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if last {
+			n[t] = value
+			return n, nil
+		}
+		child, ok := n[t]
+		if !ok {
+			return nil, errors.New("PATH_NOT_FOUND")
+		}
+		v, err := addAt(child, tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[t] = v
+		return n, nil
+	case []interface{}:
+		i, err := arrayIndex(t, len(n), last)
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			n = append(n, nil)
+			copy(n[i+1:], n[i:])
+			n[i] = value
+			return n, nil
+		}
+		v, err := addAt(n[i], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = v
+		return n, nil
+	}
+	return nil, errors.New("PATH_NOT_FOUND")
+}
+
+// pointerRemove returns doc without the value at ptr, and the removed value.
+func pointerRemove(doc interface{}, ptr string) (interface{}, interface{}, error) {
+	tokens, err := splitPointer(ptr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil, errors.New("BAD_PATCH")
+	}
+	return removeAt(doc, tokens)
+}
+
+func removeAt(node interface{}, tokens []string) (interface{}, interface{}, error) {
+	t := tokens[0]
+	last := len(tokens) == 1
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[t]
+		if !ok {
+			return nil, nil, errors.New("PATH_NOT_FOUND")
+		}
+		if last {
+			delete(n, t)
+			return n, child, nil
+		}
+		v, removed, err := removeAt(child, tokens[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		n[t] = v
+		return n, removed, nil
+	case []interface{}:
+		i, err := arrayIndex(t, len(n), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if last {
+			removed := n[i]
+			return append(n[:i:i], n[i+1:]...), removed, nil
+		}
+		v, removed, err := removeAt(n[i], tokens[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		n[i] = v
+		return n, removed, nil
+	}
+	return nil, nil, errors.New("PATH_NOT_FOUND")
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch n := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(n))
+		for k, e := range n {
+			m[k] = deepCopy(e)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(n))
+		for i, e := range n {
+			s[i] = deepCopy(e)
+		}
+		return s
+	}
+	return v
+}
@@ -0,0 +1,354 @@
+package utils
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Phone parses phone numbers written in national or international format
+// and normalizes them to E.164.
+type Phone struct {
+	// Types lists the number types Parse accepts, any type when empty.
+	Types []string
+}
+
+// PhoneNumber is a parsed phone number.
+type PhoneNumber struct {
+	E164     string `json:"e164"`
+	Region   string `json:"region"` // alpha-2 code of the country
+	Calling  string `json:"calling_code"`
+	National string `json:"national"` // national significant number
+	Type     string `json:"type"`
+}
+
+// Number types told apart by the numbering plans Vibe knows. Other numbers
+// are of type PhoneUnknown.
+const (
+	PhoneMobile            = "mobile"
+	PhoneFixedLine         = "fixed_line"
+	PhoneFixedLineOrMobile = "fixed_line_or_mobile"
+	PhoneTollFree          = "toll_free"
+	PhonePremiumRate       = "premium_rate"
+	PhoneUnknown           = "unknown"
+)
+
+// phonePlan is the numbering plan of a calling code. Prefixes are matched
+// on the national significant number, longest first.
+type phonePlan struct {
+	lengths [2]int // shortest and longest national significant number
+	trunk   string // dialed before national numbers, dropped in E.164
+	types   map[string]string
+	regions map[string]string // regions sharing the code, by prefix
+	main    string            // region of the code when nothing else tells
+	other   string            // type of numbers matching no prefix, unknown when empty
+}
+
+var phonePlans = map[string]phonePlan{
+	"1": {lengths: [2]int{10, 10}, trunk: "1", main: "US", other: PhoneFixedLineOrMobile, types: map[string]string{
+		"800": PhoneTollFree, "833": PhoneTollFree, "844": PhoneTollFree, "855": PhoneTollFree,
+		"866": PhoneTollFree, "877": PhoneTollFree, "888": PhoneTollFree, "900": PhonePremiumRate,
+	}},
+	"7": {lengths: [2]int{10, 10}, trunk: "8", main: "RU", regions: map[string]string{"6": "KZ", "7": "KZ"}, types: map[string]string{
+		"9": PhoneMobile, "3": PhoneFixedLine, "4": PhoneFixedLine, "8": PhoneFixedLine,
+		"800": PhoneTollFree, "809": PhonePremiumRate, "70": PhoneMobile, "77": PhoneMobile, "71": PhoneFixedLine, "72": PhoneFixedLine,
+	}},
+	"27": {lengths: [2]int{9, 9}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"6": PhoneMobile, "7": PhoneMobile, "81": PhoneMobile, "82": PhoneMobile, "83": PhoneMobile,
+		"84": PhoneMobile, "80": PhoneTollFree, "86": PhonePremiumRate,
+	}},
+	"31": {lengths: [2]int{9, 9}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"6": PhoneMobile, "800": PhoneTollFree, "90": PhonePremiumRate,
+	}},
+	"32": {lengths: [2]int{8, 9}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"4": PhoneMobile, "800": PhoneTollFree, "90": PhonePremiumRate,
+	}},
+	"33": {lengths: [2]int{9, 9}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"6": PhoneMobile, "7": PhoneMobile, "80": PhoneTollFree, "89": PhonePremiumRate,
+	}},
+	"34": {lengths: [2]int{9, 9}, other: PhoneFixedLine, types: map[string]string{
+		"6": PhoneMobile, "7": PhoneMobile, "900": PhoneTollFree, "800": PhoneTollFree,
+		"803": PhonePremiumRate, "806": PhonePremiumRate, "807": PhonePremiumRate,
+	}},
+	"39": {lengths: [2]int{6, 11}, main: "IT", types: map[string]string{
+		"0": PhoneFixedLine, "3": PhoneMobile, "800": PhoneTollFree, "803": PhoneTollFree, "89": PhonePremiumRate,
+	}},
+	"41": {lengths: [2]int{9, 9}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"7": PhoneMobile, "800": PhoneTollFree, "90": PhonePremiumRate,
+	}},
+	"43": {lengths: [2]int{4, 13}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"6": PhoneMobile, "800": PhoneTollFree, "900": PhonePremiumRate, "930": PhonePremiumRate,
+	}},
+	"44": {lengths: [2]int{9, 10}, trunk: "0", main: "GB", types: map[string]string{
+		"1": PhoneFixedLine, "2": PhoneFixedLine, "7": PhoneMobile, "70": PhoneUnknown, "76": PhoneUnknown,
+		"800": PhoneTollFree, "808": PhoneTollFree, "9": PhonePremiumRate,
+	}},
+	"45": {lengths: [2]int{8, 8}, other: PhoneFixedLineOrMobile, types: map[string]string{
+		"80": PhoneTollFree, "90": PhonePremiumRate,
+	}},
+	"46": {lengths: [2]int{7, 13}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"70": PhoneMobile, "72": PhoneMobile, "73": PhoneMobile, "76": PhoneMobile, "79": PhoneMobile,
+		"20": PhoneTollFree, "900": PhonePremiumRate, "939": PhonePremiumRate, "944": PhonePremiumRate,
+	}},
+	"47": {lengths: [2]int{8, 8}, main: "NO", types: map[string]string{
+		"4": PhoneMobile, "9": PhoneMobile, "2": PhoneFixedLine, "3": PhoneFixedLine, "5": PhoneFixedLine,
+		"6": PhoneFixedLine, "7": PhoneFixedLine, "800": PhoneTollFree, "82": PhonePremiumRate,
+	}},
+	"48": {lengths: [2]int{9, 9}, other: PhoneFixedLine, types: map[string]string{
+		"45": PhoneMobile, "5": PhoneMobile, "6": PhoneMobile, "72": PhoneMobile, "73": PhoneMobile,
+		"78": PhoneMobile, "79": PhoneMobile, "88": PhoneMobile, "800": PhoneTollFree, "70": PhonePremiumRate,
+	}},
+	"49": {lengths: [2]int{6, 13}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"15": PhoneMobile, "16": PhoneMobile, "17": PhoneMobile, "800": PhoneTollFree, "900": PhonePremiumRate,
+	}},
+	"52": {lengths: [2]int{10, 10}, other: PhoneFixedLineOrMobile, types: map[string]string{
+		"800": PhoneTollFree, "900": PhonePremiumRate,
+	}},
+	"55": {lengths: [2]int{10, 11}, trunk: "0", other: PhoneFixedLineOrMobile, types: map[string]string{
+		"800": PhoneTollFree,
+	}},
+	"61": {lengths: [2]int{9, 9}, trunk: "0", main: "AU", types: map[string]string{
+		"2": PhoneFixedLine, "3": PhoneFixedLine, "7": PhoneFixedLine, "8": PhoneFixedLine, "4": PhoneMobile,
+		"1800": PhoneTollFree, "190": PhonePremiumRate,
+	}},
+	"64": {lengths: [2]int{8, 10}, trunk: "0", main: "NZ", types: map[string]string{
+		"2": PhoneMobile, "3": PhoneFixedLine, "4": PhoneFixedLine, "6": PhoneFixedLine, "7": PhoneFixedLine,
+		"9": PhoneFixedLine, "800": PhoneTollFree, "508": PhoneTollFree, "900": PhonePremiumRate,
+	}},
+	"65": {lengths: [2]int{8, 8}, types: map[string]string{
+		"6": PhoneFixedLine, "8": PhoneMobile, "9": PhoneMobile, "1800": PhoneTollFree,
+	}},
+	"81": {lengths: [2]int{9, 10}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"70": PhoneMobile, "80": PhoneMobile, "90": PhoneMobile, "120": PhoneTollFree, "800": PhoneTollFree,
+		"990": PhonePremiumRate,
+	}},
+	"82": {lengths: [2]int{8, 10}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"10": PhoneMobile, "80": PhoneTollFree, "60": PhonePremiumRate,
+	}},
+	"86": {lengths: [2]int{10, 11}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"13": PhoneMobile, "14": PhoneMobile, "15": PhoneMobile, "16": PhoneMobile, "17": PhoneMobile,
+		"18": PhoneMobile, "19": PhoneMobile, "400": PhoneTollFree, "800": PhoneTollFree,
+	}},
+	"91": {lengths: [2]int{10, 10}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"6": PhoneMobile, "7": PhoneMobile, "8": PhoneMobile, "9": PhoneMobile, "1800": PhoneTollFree,
+	}},
+	"351": {lengths: [2]int{9, 9}, types: map[string]string{
+		"2": PhoneFixedLine, "9": PhoneMobile, "800": PhoneTollFree, "760": PhonePremiumRate,
+	}},
+	"353": {lengths: [2]int{7, 9}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"8": PhoneMobile, "1800": PhoneTollFree, "15": PhonePremiumRate,
+	}},
+	"852": {lengths: [2]int{8, 8}, types: map[string]string{
+		"2": PhoneFixedLine, "3": PhoneFixedLine, "5": PhoneMobile, "6": PhoneMobile, "9": PhoneMobile,
+		"800": PhoneTollFree,
+	}},
+	"886": {lengths: [2]int{8, 9}, trunk: "0", other: PhoneFixedLine, types: map[string]string{
+		"9": PhoneMobile, "800": PhoneTollFree,
+	}},
+}
+
+var (
+	// phoneRegions lists the regions of each calling code.
+	phoneRegions = map[string][]string{}
+	// phonePrefixes holds the type prefixes of each plan, longest first.
+	phonePrefixes = map[string][]string{}
+)
+
+func init() {
+	for _, c := range countries {
+		phoneRegions[c.Calling] = append(phoneRegions[c.Calling], c.Alpha2)
+	}
+	for code, plan := range phonePlans {
+		prefixes := []string{}
+		for prefix := range plan.types {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Slice(prefixes, func(i, j int) bool {
+			if len(prefixes[i]) != len(prefixes[j]) {
+				return len(prefixes[i]) > len(prefixes[j])
+			}
+			return prefixes[i] < prefixes[j]
+		})
+		phonePrefixes[code] = prefixes
+	}
+}
+
+// Parse reads a phone number. Numbers without a leading + or international
+// call prefix are read as national numbers of region, an alpha-2 code.
+func (p *Phone) Parse(number, region string) (*PhoneNumber, error) {
+	digits, intl, err := phoneDigits(number)
+	if err != nil {
+		return nil, err
+	}
+	region = strings.ToUpper(region)
+
+	calling := ""
+	if info, ok := new(Country).Lookup(region); ok && len(region) == 2 {
+		calling = info.Calling
+	} else {
+		region = ""
+	}
+	if !intl {
+		switch {
+		case calling == "1" && strings.HasPrefix(digits, "011"):
+			digits, intl = digits[3:], true
+		case calling != "1" && strings.HasPrefix(digits, "00"):
+			digits, intl = digits[2:], true
+		}
+	}
+
+	n := &PhoneNumber{}
+	if intl {
+		for l := 1; l <= 3 && l < len(digits); l++ {
+			if _, ok := phoneRegions[digits[:l]]; ok {
+				n.Calling, n.National = digits[:l], digits[l:]
+				break
+			}
+		}
+		if n.Calling == "" {
+			return nil, errors.New("UNKNOWN_CALLING_CODE")
+		}
+		// "+44 (0)20 ..." keeps the trunk prefix for those dialing at home.
+		if plan := phonePlans[n.Calling]; plan.trunk == "0" && strings.HasPrefix(n.National, "0") {
+			n.National = n.National[1:]
+		}
+	} else {
+		if calling == "" {
+			return nil, errors.New("PHONE_REGION_REQUIRED")
+		}
+		n.Calling, n.National = calling, digits
+		plan, ok := phonePlans[calling]
+		trunk := plan.trunk
+		if !ok {
+			trunk = "0" // the most common trunk prefix
+		}
+		if trunk != "" && strings.HasPrefix(n.National, trunk) && (calling != "1" || len(n.National) == 11) {
+			n.National = n.National[len(trunk):]
+		}
+	}
+
+	if err := checkPhoneLength(n); err != nil {
+		return nil, err
+	}
+	n.E164 = "+" + n.Calling + n.National
+	n.Region = phoneRegion(n, region)
+	n.Type = phoneType(n)
+
+	if len(p.Types) > 0 {
+		allowed := false
+		for _, t := range p.Types {
+			allowed = allowed || t == n.Type
+		}
+		if !allowed {
+			return nil, errors.New("PHONE_TYPE_NOT_ALLOWED")
+		}
+	}
+	return n, nil
+}
+
+// Format returns the display version of a number stored in E.164, or the
+// number as it is when it cannot be parsed.
+func (p *Phone) Format(e164 string) string {
+	n, err := new(Phone).Parse(e164, "")
+	if err != nil {
+		return e164
+	}
+	return n.Display()
+}
+
+// Display returns the number in international format with its digits
+// grouped, such as "+1 415-555-2671" or "+33 612 345 678".
+func (n *PhoneNumber) Display() string {
+	if n.Calling == "1" && len(n.National) == 10 {
+		return "+1 " + n.National[:3] + "-" + n.National[3:6] + "-" + n.National[6:]
+	}
+
+	groups := []string{}
+	rest := n.National
+	for len(rest) > 4 {
+		size := 3
+		if len(rest) == 5 {
+			size = 2
+		}
+		groups = append(groups, rest[:size])
+		rest = rest[size:]
+	}
+	groups = append(groups, rest)
+	return "+" + n.Calling + " " + strings.Join(groups, " ")
+}
+
+// phoneDigits strips the separators people write numbers with. intl tells
+// if the number started with +.
+func phoneDigits(number string) (string, bool, error) {
+	number = strings.TrimSpace(number)
+	intl := strings.HasPrefix(number, "+")
+	if intl {
+		number = number[1:]
+	}
+
+	var b strings.Builder
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case strings.ContainsRune(" \t -.()/", r):
+		default:
+			return "", false, errors.New("BAD_PHONE")
+		}
+	}
+	if b.Len() == 0 {
+		return "", false, errors.New("BAD_PHONE")
+	}
+	return b.String(), intl, nil
+}
+
+func checkPhoneLength(n *PhoneNumber) error {
+	min, max := 4, 15-len(n.Calling)
+	if plan, ok := phonePlans[n.Calling]; ok {
+		min, max = plan.lengths[0], plan.lengths[1]
+	}
+	if len(n.National) < min {
+		return errors.New("PHONE_TOO_SHORT")
+	}
+	if len(n.National) > max {
+		return errors.New("PHONE_TOO_LONG")
+	}
+	return nil
+}
+
+// phoneRegion tells the region of a number whose calling code may be shared
+// by several regions. The region it was parsed for wins when it shares it.
+func phoneRegion(n *PhoneNumber, region string) string {
+	plan := phonePlans[n.Calling]
+	for prefix, r := range plan.regions {
+		if strings.HasPrefix(n.National, prefix) {
+			return r
+		}
+	}
+	regions := phoneRegions[n.Calling]
+	for _, r := range regions {
+		if r == region {
+			return r
+		}
+	}
+	if plan.main != "" {
+		return plan.main
+	}
+	return regions[0]
+}
+
+func phoneType(n *PhoneNumber) string {
+	plan, ok := phonePlans[n.Calling]
+	if !ok {
+		return PhoneUnknown
+	}
+	for _, prefix := range phonePrefixes[n.Calling] {
+		if strings.HasPrefix(n.National, prefix) {
+			return plan.types[prefix]
+		}
+	}
+	if plan.other == "" {
+		return PhoneUnknown
+	}
+	return plan.other
+}
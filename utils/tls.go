@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertCheckInterval is the least time between two checks of the certificate
+// files for a renewal.
+var CertCheckInterval = 10 * time.Second
+
+// CertReloader serves a certificate and its key from files through
+// tls.Config.GetCertificate, loading them again once they change so a
+// renewed certificate is served without a restart.
+type CertReloader struct {
+	CertFile string
+	KeyFile  string
+	// OnError is told of a renewal that could not be loaded, the previous
+	// certificate is served meanwhile.
+	OnError func(error)
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+// NewCertReloader loads the certificate of certFile and keyFile.
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{CertFile: certFile, KeyFile: keyFile}
+	modTime, err := r.lastChange()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) >= CertCheckInterval {
+		r.checked = time.Now()
+		modTime, err := r.lastChange()
+		if err == nil && modTime.After(r.modTime) {
+			err = r.load(modTime)
+		}
+		if err != nil && r.OnError != nil {
+			r.OnError(err)
+		}
+	}
+	return r.cert, nil
+}
+
+// lastChange is the latest modification time of both files.
+func (r *CertReloader) lastChange() (time.Time, error) {
+	var last time.Time
+	for _, name := range []string{r.CertFile, r.KeyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return last, err
+		}
+		if info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return last, nil
+}
+
+func (r *CertReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.CertFile, r.KeyFile)
+	if err != nil {
+		return err
+	}
+	r.cert, r.modTime, r.checked = &cert, modTime, time.Now()
+	return nil
+}
@@ -0,0 +1,198 @@
+// Package vibe sets up the Vibe member API on Echo, as a server of its own
+// with New or under a prefix of an existing application with Mount.
+//
+//	e, err := vibe.New(nil,
+//		vibe.WithoutGroups(wrappers.GroupBilling),
+//		vibe.WithMailer(mailer),
+//	)
+//
+// The configuration, stores and hooks of Vibe are global, one Vibe is set up
+// per process.
+package vibe
+
+import (
+	"fmt"
+	"github.com/Festum/Vibe/controllers"
+	"github.com/Festum/Vibe/models"
+	"github.com/Festum/Vibe/wrappers"
+	"github.com/labstack/echo"
+	"github.com/labstack/echo/middleware"
+	"strings"
+)
+
+// Option changes how Vibe is set up.
+type Option func(*setup)
+
+type setup struct {
+	disabled   map[string]bool
+	only       []string
+	middleware []echo.MiddlewareFunc
+	blobs      controllers.BlobStore
+	gateway    controllers.PaymentGateway
+	mailer     controllers.Mailer
+	hooks      []func(*controllers.HookRegistry)
+}
+
+// WithoutGroups leaves the routes of groups, such as wrappers.GroupAdmin,
+// out of the API and its documentation.
+func WithoutGroups(groups ...string) Option {
+	return func(s *setup) {
+		for _, g := range groups {
+			s.disabled[g] = true
+		}
+	}
+}
+
+// WithGroups serves the routes of groups only, less those of WithoutGroups.
+func WithGroups(groups ...string) Option {
+	return func(s *setup) {
+		s.only = append(s.only, groups...)
+	}
+}
+
+// WithMiddleware runs m on every route of Vibe, before its token checks.
+func WithMiddleware(m ...echo.MiddlewareFunc) Option {
+	return func(s *setup) {
+		s.middleware = append(s.middleware, m...)
+	}
+}
+
+// WithBlobStore keeps uploaded files such as avatars in store instead of the
+// one chosen by the [avatar] section.
+func WithBlobStore(store controllers.BlobStore) Option {
+	return func(s *setup) {
+		s.blobs = store
+	}
+}
+
+// WithGateway vaults the cards of billing profiles with g.
+func WithGateway(g controllers.PaymentGateway) Option {
+	return func(s *setup) {
+		s.gateway = g
+	}
+}
+
+// WithMailer delivers the notifications of users with m.
+func WithMailer(m controllers.Mailer) Option {
+	return func(s *setup) {
+		s.mailer = m
+	}
+}
+
+// WithHooks registers lifecycle callbacks, fn is called with the registry
+// of Vibe.
+func WithHooks(fn func(*controllers.HookRegistry)) Option {
+	return func(s *setup) {
+		s.hooks = append(s.hooks, fn)
+	}
+}
+
+// New returns an Echo instance serving Vibe at its root, with its error
+// handler, request IDs, logging and panic recovery. cfg replaces the loaded
+// configuration unless nil.
+func New(cfg *models.Config, opts ...Option) (*echo.Echo, error) {
+	h, err := configure(cfg, "", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	e := echo.New()
+	e.SetHTTPErrorHandler(h.HandleError)
+	e.Use(h.RequestID)
+	e.Use(middleware.Logger())
+	e.Use(middleware.Recover())
+	h.Mount(e)
+	return e, nil
+}
+
+// Mount serves Vibe under prefix, such as "/members", on e. The error
+// handler of e is left alone, the routes of Vibe answer their errors as
+// problems themselves.
+func Mount(e *echo.Echo, prefix string, cfg *models.Config, opts ...Option) error {
+	prefix = strings.TrimRight(prefix, "/")
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	h, err := configure(cfg, prefix, opts)
+	if err != nil {
+		return err
+	}
+
+	h.Middleware = append([]echo.MiddlewareFunc{h.RequestID, h.Problems}, h.Middleware...)
+	h.Mount(e)
+	return nil
+}
+
+// configure applies cfg and opts and returns the handlers to mount.
+func configure(cfg *models.Config, prefix string, opts []Option) (*wrappers.Handlers, error) {
+	if cfg != nil {
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		models.Use(cfg)
+	}
+
+	s := &setup{disabled: map[string]bool{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.restrict(); err != nil {
+		return nil, err
+	}
+
+	if s.blobs != nil {
+		controllers.Blobs = s.blobs
+	}
+	if s.gateway != nil {
+		controllers.Gateway = s.gateway
+	}
+	if s.mailer != nil {
+		controllers.Mail = s.mailer
+	}
+	for _, fn := range s.hooks {
+		fn(controllers.Hooks)
+	}
+	controllers.BasePath = prefix
+
+	return &wrappers.Handlers{
+		Prefix:     prefix,
+		Disabled:   s.disabled,
+		Middleware: s.middleware,
+	}, nil
+}
+
+// restrict disables the groups left out by WithGroups and refuses unknown
+// group names.
+func (s *setup) restrict() error {
+	known := map[string]bool{}
+	for _, g := range wrappers.Groups {
+		known[g] = true
+	}
+	for g := range s.disabled {
+		if !known[g] {
+			return unknownGroup(g)
+		}
+	}
+	for _, g := range s.only {
+		if !known[g] {
+			return unknownGroup(g)
+		}
+	}
+
+	if len(s.only) > 0 {
+		only := map[string]bool{}
+		for _, g := range s.only {
+			only[g] = true
+		}
+		for _, g := range wrappers.Groups {
+			if !only[g] {
+				s.disabled[g] = true
+			}
+		}
+	}
+	return nil
+}
+
+func unknownGroup(g string) error {
+	return fmt.Errorf("unknown route group %q, known: %s", g, strings.Join(wrappers.Groups, ", "))
+}
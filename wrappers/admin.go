@@ -0,0 +1,171 @@
+package wrappers
+
+import (
+	"errors"
+	"github.com/Festum/Vibe/controllers"
+	"github.com/labstack/echo"
+	"gopkg.in/mgo.v2/bson"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AdminOnly lets the request through only when the token issuer is an enabled
+// admin. The role is read from the store so a demoted admin loses access
+// before the token expires.
+func (h *Handlers) AdminOnly(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		u := new(controllers.User)
+		ut := u.ParseToken(c.Get("user"))
+		u.Username, _ = ut["iss"].(string)
+		if u.Username == "" {
+			return echo.ErrUnauthorized
+		}
+		if err := u.Get(); err != nil || u.Role != "admin" || u.IsDisabled {
+			return controllers.ErrForbidden
+		}
+		return next(c)
+	}
+}
+
+func (h *Handlers) ListUsers(c echo.Context) error {
+	q := &controllers.UserQuery{
+		Role:    c.QueryParam("role"),
+		Country: c.QueryParam("country"),
+		Phone:   c.QueryParam("phone"),
+		Search:  c.QueryParam("q"),
+		Sort:    c.QueryParam("sort"),
+		Cursor:  c.QueryParam("cursor"),
+		Prefix: map[string]string{
+			"username":     c.QueryParam("username"),
+			"email":        c.QueryParam("email"),
+			"display_name": c.QueryParam("display_name"),
+		},
+	}
+
+	var err error
+	if v := c.QueryParam("disabled"); v != "" {
+		disabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return badRequest(errors.New("disabled: " + err.Error()))
+		}
+		q.Disabled = &disabled
+	}
+	if v := c.QueryParam("deleted"); v != "" {
+		if q.Deleted, err = strconv.ParseBool(v); err != nil {
+			return badRequest(errors.New("deleted: " + err.Error()))
+		}
+	}
+	if v := c.QueryParam("limit"); v != "" {
+		if q.Limit, err = strconv.Atoi(v); err != nil {
+			return badRequest(errors.New("limit: " + err.Error()))
+		}
+	}
+	for param, t := range map[string]*time.Time{
+		"created_after": &q.CreatedAfter,
+		"created_until": &q.CreatedUntil,
+		"login_after":   &q.LoginAfter,
+		"login_until":   &q.LoginUntil,
+	} {
+		if v := c.QueryParam(param); v != "" {
+			if *t, err = time.Parse(time.RFC3339, v); err != nil {
+				return badRequest(errors.New(param + ": " + err.Error()))
+			}
+		}
+	}
+
+	page, err := q.Find()
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+func (h *Handlers) GetUser(c echo.Context) error {
+	u := userFromParam(c.Param("id"))
+	if err := u.Get(); err != nil {
+		return err
+	}
+
+	setETag(c, u)
+	return c.JSON(http.StatusOK, u)
+}
+
+func (h *Handlers) UpdateUser(c echo.Context) error {
+	target := userFromParam(c.Param("id"))
+	if err := target.Get(); err != nil {
+		return err
+	}
+	if isPatchRequest(c) {
+		return patchAccount(c, target, true)
+	}
+
+	u := new(controllers.User)
+	fields, err := bindUpdate(c, u)
+	if err != nil {
+		return badRequest(err)
+	}
+	// The path decides which account is changed, never the body.
+	u.ID, u.Username = "", target.Username
+	u.Actor = actorFromContext(c)
+	v, err := ifMatch(c, true)
+	if err != nil {
+		return err
+	}
+	u.Version = v
+
+	if err := u.UpdateFields(fields, true); err != nil {
+		return err
+	}
+
+	setETag(c, u)
+	return c.JSON(http.StatusOK, u)
+}
+
+func (h *Handlers) DeleteUser(c echo.Context) error {
+	u := userFromParam(c.Param("id"))
+	if err := u.Get(); err != nil {
+		return err
+	}
+	u.Actor = actorFromContext(c)
+	if err := u.Delete(); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RestoreUser brings back an account deleted less than DeleteGrace ago.
+func (h *Handlers) RestoreUser(c echo.Context) error {
+	u := userFromParam(c.Param("id"))
+	u.Actor = actorFromContext(c)
+	if err := u.Restore(); err != nil {
+		return err
+	}
+
+	setETag(c, u)
+	return c.JSON(http.StatusOK, u)
+}
+
+// AnonymizeUser erases the personal data of an account and keeps its id.
+func (h *Handlers) AnonymizeUser(c echo.Context) error {
+	u := userFromParam(c.Param("id"))
+	u.Actor = actorFromContext(c)
+	if err := u.Anonymize(); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, u)
+}
+
+// userFromParam accepts either an object id or a username in the path.
+func userFromParam(id string) *controllers.User {
+	u := new(controllers.User)
+	if bson.IsObjectIdHex(id) {
+		u.ID = bson.ObjectIdHex(id)
+	} else {
+		u.Username = id
+	}
+	return u
+}
@@ -0,0 +1,68 @@
+package wrappers
+
+import (
+	"errors"
+	"github.com/Festum/Vibe/controllers"
+	"github.com/labstack/echo"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// auditVerification is the outcome of a check of the audit chain.
+type auditVerification struct {
+	OK      bool   `json:"ok"`
+	Checked int64  `json:"checked"`
+	Error   string `json:"error,omitempty"`
+}
+
+func (h *Handlers) ListAudit(c echo.Context) error {
+	q := &controllers.AuditQuery{
+		Actor:  c.QueryParam("actor"),
+		Target: c.QueryParam("target"),
+		Action: c.QueryParam("action"),
+	}
+
+	var err error
+	if v := c.QueryParam("after"); v != "" {
+		if q.AfterSeq, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return badRequest(errors.New("after: " + err.Error()))
+		}
+	}
+	if v := c.QueryParam("limit"); v != "" {
+		if q.Limit, err = strconv.Atoi(v); err != nil {
+			return badRequest(errors.New("limit: " + err.Error()))
+		}
+	}
+	for param, t := range map[string]*time.Time{
+		"since": &q.Since,
+		"until": &q.Until,
+	} {
+		if v := c.QueryParam(param); v != "" {
+			if *t, err = time.Parse(time.RFC3339, v); err != nil {
+				return badRequest(errors.New(param + ": " + err.Error()))
+			}
+		}
+	}
+
+	entries, err := q.Find()
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+func (h *Handlers) VerifyAudit(c echo.Context) error {
+	checked, err := controllers.VerifyAudit()
+	if err != nil && !strings.HasPrefix(err.Error(), "AUDIT_CHAIN_BROKEN") {
+		return err
+	}
+	res := &auditVerification{OK: err == nil, Checked: checked}
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,81 @@
+package wrappers
+
+import (
+	"github.com/Festum/Vibe/controllers"
+	"github.com/labstack/echo"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+const maxAvatarUpload = 32 << 20
+
+// avatarUpload is the multipart form of an avatar upload.
+type avatarUpload struct {
+	Avatar []byte `json:"avatar"`
+}
+
+// UploadAvatar takes the picture of the multipart "avatar" field. Without
+// one the caller gets a generated identicon.
+func (h *Handlers) UploadAvatar(c echo.Context) error {
+	ut := new(controllers.User).ParseToken(c.Get("user"))
+	u := &controllers.User{Username: ut["iss"].(string)}
+	if err := u.Get(); err != nil {
+		return err
+	}
+	u.Actor = actorFromContext(c)
+
+	fh, err := c.FormFile("avatar")
+	if err == http.ErrMissingFile {
+		return avatarSaved(c, u, u.SetIdenticon())
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return badRequest(err)
+	}
+	defer f.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(f, maxAvatarUpload))
+	if err != nil {
+		return badRequest(err)
+	}
+
+	return avatarSaved(c, u, u.SetAvatar(data))
+}
+
+// RemoveAvatar drops the caller's picture for a generated identicon.
+func (h *Handlers) RemoveAvatar(c echo.Context) error {
+	ut := new(controllers.User).ParseToken(c.Get("user"))
+	u := &controllers.User{Username: ut["iss"].(string)}
+	if err := u.Get(); err != nil {
+		return err
+	}
+	u.Actor = actorFromContext(c)
+
+	return avatarSaved(c, u, u.SetIdenticon())
+}
+
+func avatarSaved(c echo.Context, u *controllers.User, err error) error {
+	if err != nil {
+		return err
+	}
+
+	setETag(c, u)
+	return c.JSON(http.StatusOK, u)
+}
+
+// ServeAvatar streams an avatar variant. Names change with every upload so
+// responses are cached for good.
+func (h *Handlers) ServeAvatar(c echo.Context) error {
+	name := c.Param("id") + "/" + c.Param("stamp") + "/" + c.Param("file")
+	r, contentType, err := controllers.OpenAvatar(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	c.Response().Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	return c.Stream(http.StatusOK, contentType, r)
+}
@@ -0,0 +1,55 @@
+package wrappers
+
+import (
+	"github.com/Festum/Vibe/controllers"
+	"github.com/Festum/Vibe/models"
+	"github.com/labstack/echo"
+	"net/http"
+)
+
+func (h *Handlers) GetBilling(c echo.Context) error {
+	ut := new(controllers.User).ParseToken(c.Get("user"))
+	u := &controllers.User{Username: ut["iss"].(string)}
+	b, err := u.GetBilling()
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, b)
+}
+
+// SaveBilling replaces the caller's payment method. The response carries the
+// masked card only.
+func (h *Handlers) SaveBilling(c echo.Context) error {
+	ut := new(controllers.User).ParseToken(c.Get("user"))
+	if controllers.IsImpersonated(ut) {
+		return errImpersonating
+	}
+
+	b := new(models.Billing)
+	if err := c.Bind(b); err != nil {
+		return badRequest(err)
+	}
+	u := &controllers.User{Username: ut["iss"].(string)}
+	u.Actor = actorFromContext(c)
+	if err := u.SaveBilling(b); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, b)
+}
+
+func (h *Handlers) RemoveBilling(c echo.Context) error {
+	ut := new(controllers.User).ParseToken(c.Get("user"))
+	if controllers.IsImpersonated(ut) {
+		return errImpersonating
+	}
+
+	u := &controllers.User{Username: ut["iss"].(string)}
+	u.Actor = actorFromContext(c)
+	if err := u.RemoveBilling(); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -2,20 +2,36 @@ package wrappers
 
 import (
 	"github.com/labstack/echo"
+	"github.com/labstack/echo/engine/standard"
 	"github.com/labstack/echo/middleware"
 	"github.com/Festum/Vibe/controllers"
 	"github.com/Festum/Vibe/models"
+	"net"
 	"net/http"
+	"strings"
 )
 
 type (
 	Handlers struct {
 		User controllers.User
+		// Prefix is the path the routes are mounted under, "" for the root.
+		Prefix string
+		// Disabled groups are neither mounted nor documented.
+		Disabled map[string]bool
+		// Middleware runs on every route, before its token checks.
+		Middleware []echo.MiddlewareFunc
+	}
+
+	// credentials are the body of a login, by email or username.
+	credentials struct {
+		Email    string `json:"email"`
+		Username string `json:"username"`
+		Password string `json:"password"`
 	}
-)
 
-var (
-	conf = models.Config{}.Init()
+	tokenResponse struct {
+		Token string `json:"token"`
+	}
 )
 
 func (h *Handlers) Init() {}
@@ -25,100 +41,226 @@ func (h *Handlers) Get(c echo.Context) error {
 	ut := u.ParseToken(c.Get("user"))
 	u.Username = ut["iss"].(string)
 	if err := u.Get(); err != nil {
-		return c.NoContent(http.StatusNotFound)
+		return err
 	}
 
+	setETag(c, u)
 	return c.JSON(http.StatusOK, u)
 }
 
+// Update changes the fields of an account present in the body, the others
+// keep their value.
 func (h *Handlers) Update(c echo.Context) error {
 	u := new(controllers.User)
-	if err := c.Bind(u); err != nil {
-		return c.String(http.StatusBadRequest, err.Error())
+	fields, err := bindUpdate(c, u)
+	if err != nil {
+		return badRequest(err)
+	}
+	u.ID = ""
+
+	// A PUT replaces the account and must name the version it is based on.
+	v, err := ifMatch(c, c.Request().Method() == echo.PUT)
+	if err != nil {
+		return err
 	}
+	u.Version = v
 
 	ut := u.ParseToken(c.Get("user"))
 	issuer := controllers.User{Username: ut["iss"].(string)}
+	if err := issuer.Get(); err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	// Support staff acting as a user must not take over the account.
+	if controllers.IsImpersonated(ut) && u.Password != "" {
+		return errImpersonating
+	}
 
-	if ut["iss"].(string) != u.Username && issuer.Role != "admin" {
-		return c.NoContent(http.StatusUnauthorized)
+	if issuer.Username != u.Username && issuer.Role != "admin" {
+		return controllers.ErrForbidden
 	}
 
-	if err := u.Update(); err != nil {
-		return c.String(http.StatusInternalServerError, err.Error())
+	u.Actor = actorFromContext(c)
+	if err := u.UpdateFields(fields, issuer.Role == "admin" && !controllers.IsImpersonated(ut)); err != nil {
+		return err
 	}
 
+	setETag(c, u)
 	return c.JSON(http.StatusOK, u)
 }
 
 func (h *Handlers) Delete(c echo.Context) error {
+	if controllers.IsImpersonated(new(controllers.User).ParseToken(c.Get("user"))) {
+		return errImpersonating
+	}
+
 	u := new(controllers.User)
 	if err := c.Bind(u); err != nil {
-		ut := u.ParseToken(c.Get("user"))
-		u.Username = ut["iss"].(string)
-		u.Get()
+		return badRequest(err)
+	}
+	if u.ID == "" && u.Email == "" && u.Username == "" {
+		return errAccountRequired
+	}
+	if err := u.Get(); err != nil {
+		return err
+	}
+
+	ut := u.ParseToken(c.Get("user"))
+	issuer := controllers.User{Username: ut["iss"].(string)}
+	if err := issuer.Get(); err != nil {
+		return echo.ErrUnauthorized
 	}
+	if issuer.Username != u.Username && issuer.Role != "admin" {
+		return controllers.ErrForbidden
+	}
+
+	u.Actor = actorFromContext(c)
 	if err := u.Delete(); err != nil {
-		return c.String(http.StatusInternalServerError, err.Error())
+		return err
 	}
-	return nil
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Anonymize erases the caller's personal data, the account cannot be used
+// afterwards.
+func (h *Handlers) Anonymize(c echo.Context) error {
+	ut := new(controllers.User).ParseToken(c.Get("user"))
+	if controllers.IsImpersonated(ut) {
+		return errImpersonating
+	}
+
+	u := &controllers.User{Username: ut["iss"].(string)}
+	u.Actor = actorFromContext(c)
+	if err := u.Anonymize(); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
 }
 
 func (h *Handlers) JWTCheck() middleware.JWTConfig {
 	return middleware.JWTConfig{
-		SigningKey:    []byte(conf.JWT.SigningKey),
-		SigningMethod: conf.JWT.SigningMethod,
+		// Requests authenticated by ClientCert carry no token.
+		Skipper: func(c echo.Context) bool {
+			return c.Get("user") != nil
+		},
+		SigningKey:    []byte(models.Conf().JWT.SigningKey),
+		SigningMethod: models.Conf().JWT.SigningMethod,
+	}
+}
+
+// ClientCert authenticates a request without a token by its verified client
+// certificate of mutual TLS, as the bot or api account it maps to.
+func (h *Handlers) ClientCert(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req, ok := c.Request().(*standard.Request)
+		if !ok || req.Request.TLS == nil || len(req.Request.TLS.VerifiedChains) == 0 ||
+			c.Request().Header().Get(echo.HeaderAuthorization) != "" {
+			return next(c)
+		}
+
+		token, err := controllers.CertificateToken(req.Request.TLS.VerifiedChains[0][0])
+		if err != nil {
+			return err
+		}
+		c.Set("user", token)
+		return next(c)
 	}
 }
 
 func (h *Handlers) Login(c echo.Context) error {
 	u, pw, err := getLoginName(c)
 	if err != nil {
-		return c.String(http.StatusBadRequest, err.Error())
+		return badRequest(err)
+	}
+	if err := u.ValidateLogin(pw); err != nil {
+		return err
 	}
 	if !u.IsPass(pw) {
-		return c.NoContent(http.StatusUnauthorized)
+		return echo.ErrUnauthorized
 	}
+	u.Actor = &models.Actor{Username: u.Username, Role: u.Role, Source: "self", IP: clientIP(c)}
 	token, err := u.GenerateToken("", "", -1)
 	if err != nil {
-		return c.NoContent(http.StatusNoContent)
+		return err
 	}
+	controllers.EmitUserEvent(controllers.EventLogin, u)
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"token": token,
-	})
+	return c.JSON(http.StatusOK, &tokenResponse{Token: token})
 }
 
 func (h *Handlers) Register(c echo.Context) error {
 	u := new(controllers.User)
 	if err := c.Bind(u); err != nil {
-		return c.String(http.StatusBadRequest, err.Error())
+		return badRequest(err)
 	}
+	// Accounts registering themselves are members, other roles and the
+	// state of an account are given by administrators. Avatars are only
+	// uploaded once the account exists.
+	u.ID, u.Role, u.IsDisabled, u.Avatar = "", "member", false, ""
+	u.DeletedAt, u.PurgeAt, u.AnonymizedAt = nil, nil, nil
+	u.Actor = &models.Actor{Username: u.Username, Source: "self", IP: clientIP(c)}
 
 	if err := u.Create(); err != nil {
-		switch err.Error() {
-		case "E11000":
-			return c.NoContent(http.StatusConflict)
-		}
-		return c.String(http.StatusInternalServerError, err.Error())
+		return err
 	}
 	//TODO: Mask passwords as asterisk
 	return c.JSON(http.StatusCreated, u)
 }
 
+// actorFromContext describes the caller of a JWT protected request for the
+// audit trail.
+func actorFromContext(c echo.Context) *models.Actor {
+	ut := new(controllers.User).ParseToken(c.Get("user"))
+	actor := &models.Actor{
+		Source:         "jwt",
+		ImpersonatedBy: controllers.Impersonator(ut),
+		IP:             clientIP(c),
+	}
+	actor.Username, _ = ut["iss"].(string)
+	actor.Role, _ = ut["role"].(string)
+
+	return actor
+}
+
+// clientIP is the address of the caller. X-Real-IP and X-Forwarded-For are
+// only read from the trusted proxies of [http], anyone else could forge them.
+func clientIP(c echo.Context) string {
+	req := c.Request()
+	host, _, err := net.SplitHostPort(req.RemoteAddress())
+	if err != nil {
+		host = req.RemoteAddress()
+	}
+	proxies := models.Conf().HTTP
+	if !proxies.TrustsProxy(host) {
+		return host
+	}
+
+	if ip := strings.TrimSpace(req.Header().Get(echo.HeaderXRealIP)); ip != "" {
+		return ip
+	}
+	// The last hop not added by a trusted proxy is the client.
+	hops := strings.Split(req.Header().Get(echo.HeaderXForwardedFor), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(hops[i])
+		if ip == "" {
+			continue
+		}
+		if host = ip; !proxies.TrustsProxy(ip) {
+			break
+		}
+	}
+	return host
+}
+
 func getLoginName(c echo.Context) (*controllers.User, string, error) {
 	u := new(controllers.User)
-	user := &struct {
-		Email    string `json:"email"`
-		Username string `json:"username"`
-		Password string `json:"password"`
-	}{}
+	user := new(credentials)
 	pw := ""
 	if err := c.Bind(user); err != nil {
 		pw = c.FormValue("password")
 		u.Email = c.FormValue("email")
 		u.Username = c.FormValue("username")
-		if (u.Email == "" && u.Username == "") || pw == "" {
+		if u.Email == "" && u.Username == "" && pw == "" {
 			return u, pw, err
 		}
 	} else {
@@ -146,5 +288,5 @@ func (h *Handlers) TokenResolve(c echo.Context) error {
 	u := new(controllers.User)
 	ut := u.ParseToken(c.Get("user"))
 
-	return c.String(http.StatusOK, "Welcome "+ut["iss"].(string)+"!")
+	return c.String(http.StatusOK, msg(c, "WELCOME", map[string]interface{}{"Username": ut["iss"]}))
 }
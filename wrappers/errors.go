@@ -0,0 +1,204 @@
+package wrappers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/Festum/Vibe/controllers"
+	"github.com/Festum/Vibe/utils"
+	"github.com/labstack/echo"
+	uuid "github.com/satori/go.uuid"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+const (
+	ProblemContentType = "application/problem+json"
+	headerRequestID    = "X-Request-ID"
+)
+
+// problem is the RFC 7807 answer of a failed request. Code is stable and
+// Message is its text in the language of the caller; Errors lists the
+// refused fields of an invalid request.
+type problem struct {
+	Type      string                   `json:"type"`
+	Title     string                   `json:"title"`
+	Status    int                      `json:"status"`
+	Code      string                   `json:"code"`
+	Message   string                   `json:"message"`
+	Detail    string                   `json:"detail,omitempty"`
+	Instance  string                   `json:"instance,omitempty"`
+	RequestID string                   `json:"request_id,omitempty"`
+	Errors    []controllers.FieldError `json:"errors,omitempty"`
+}
+
+var (
+	logger = new(utils.Logger)
+
+	errImpersonating        = &controllers.Error{Kind: controllers.ErrForbidden, Code: "FORBIDDEN_WHILE_IMPERSONATING"}
+	errAccountRequired      = &controllers.Error{Kind: controllers.ErrBadRequest, Code: "ACCOUNT_REQUIRED"}
+	errPreconditionRequired = &controllers.Error{Kind: controllers.ErrBadRequest, Code: "PRECONDITION_REQUIRED"}
+	errExportGone           = &controllers.Error{Kind: controllers.ErrNotFound, Code: "GONE"}
+
+	// kindStatus answers the kinds of controller errors, in order.
+	kindStatus = []struct {
+		kind   error
+		status int
+	}{
+		{controllers.ErrValidation, http.StatusUnprocessableEntity},
+		{controllers.ErrNotFound, http.StatusNotFound},
+		{controllers.ErrConflict, http.StatusConflict},
+		{controllers.ErrForbidden, http.StatusForbidden},
+		{controllers.ErrBadRequest, http.StatusBadRequest},
+		{controllers.ErrUnavailable, http.StatusServiceUnavailable},
+	}
+
+	// codeStatus are the message codes answered more precisely than by
+	// their kind.
+	codeStatus = map[string]int{
+		"CARD_DECLINED":          http.StatusPaymentRequired,
+		"LINK_EXPIRED":           http.StatusGone,
+		"GONE":                   http.StatusGone,
+		"IMAGE_TOO_LARGE":        http.StatusRequestEntityTooLarge,
+		"UNSUPPORTED_IMAGE":      http.StatusUnsupportedMediaType,
+		"UNSUPPORTED_PATCH_TYPE": http.StatusUnsupportedMediaType,
+		"PRECONDITION_REQUIRED":  http.StatusPreconditionRequired,
+	}
+
+	// statusCodes are the message codes of errors without one.
+	statusCodes = map[int]string{
+		http.StatusBadRequest:           "BAD_REQUEST",
+		http.StatusUnauthorized:         "UNAUTHORIZED",
+		http.StatusForbidden:            "FORBIDDEN",
+		http.StatusNotFound:             "NOT_FOUND",
+		http.StatusMethodNotAllowed:     "METHOD_NOT_ALLOWED",
+		http.StatusConflict:             "CONFLICT",
+		http.StatusGone:                 "GONE",
+		http.StatusUnsupportedMediaType: "UNSUPPORTED_MEDIA_TYPE",
+		http.StatusUnprocessableEntity:  "VALIDATION_FAILED",
+		http.StatusServiceUnavailable:   "SERVICE_UNAVAILABLE",
+	}
+
+	requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+)
+
+// RequestID names every request by the X-Request-ID of the caller, or a new
+// one when it sent none usable. The id is echoed in the response and carried
+// by error answers and logs.
+func (h *Handlers) RequestID(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := c.Request().Header().Get(headerRequestID)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewV4().String()
+		}
+		c.Set("request_id", id)
+		c.Response().Header().Set(headerRequestID, id)
+		return next(c)
+	}
+}
+
+func requestID(c echo.Context) string {
+	id, _ := c.Get("request_id").(string)
+	return id
+}
+
+// badRequest refuses a request that cannot be read, such as a malformed body
+// or query parameter.
+func badRequest(err error) error {
+	return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+}
+
+// HandleError answers the errors of handlers and middleware as RFC 7807
+// problems in the language of the caller. Errors carrying a message code
+// keep it, others are answered by their status; the text of client errors
+// is kept as detail, server errors are logged instead.
+func (h *Handlers) HandleError(err error, c echo.Context) {
+	p := &problem{
+		Type:      "about:blank",
+		Status:    errorStatus(c, err),
+		Code:      err.Error(),
+		Instance:  c.Request().URL().Path(),
+		RequestID: requestID(c),
+	}
+
+	var herr *echo.HTTPError
+	if errors.As(err, &herr) {
+		p.Code = ""
+		if herr.Message != http.StatusText(herr.Code) {
+			p.Detail = herr.Message
+		}
+	}
+	if !controllers.HasMessage(p.Code) {
+		if p.Code != "" && p.Status < http.StatusInternalServerError {
+			p.Detail = err.Error()
+		}
+		p.Code = statusCodes[p.Status]
+		if p.Code == "" && p.Status < http.StatusInternalServerError {
+			p.Code = "BAD_REQUEST"
+		} else if p.Code == "" {
+			p.Code = "INTERNAL_ERROR"
+		}
+	}
+	if p.Status >= http.StatusInternalServerError {
+		p.Detail = ""
+		logger.Error(map[string]interface{}{
+			"section":    "Request",
+			"path":       p.Instance,
+			"status":     p.Status,
+			"request_id": p.RequestID,
+			"time":       time.Now(),
+		}, err.Error())
+	}
+
+	lang := locale(c)
+	p.Title = http.StatusText(p.Status)
+	p.Message = controllers.Localize(lang, p.Code, nil)
+	var verr *controllers.ValidationError
+	if errors.As(err, &verr) {
+		p.Errors = verr.Localize(lang).Fields
+	}
+
+	if c.Response().Committed() {
+		return
+	}
+	body, _ := json.Marshal(p)
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, ProblemContentType)
+	res.Header().Set("Content-Language", lang)
+	res.WriteHeader(p.Status)
+	if c.Request().Method() != echo.HEAD {
+		res.Write(body)
+	}
+}
+
+// Problems answers the errors of next with HandleError, for routes mounted
+// on an application keeping its own error handler.
+func (h *Handlers) Problems(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := next(c); err != nil {
+			h.HandleError(err, c)
+		}
+		return nil
+	}
+}
+
+// errorStatus is the HTTP status answering err. A version conflict fails the
+// precondition of a request sent with If-Match.
+func errorStatus(c echo.Context, err error) int {
+	var herr *echo.HTTPError
+	if errors.As(err, &herr) {
+		return herr.Code
+	}
+	if errors.Is(err, controllers.ErrVersionConflict) && c.Request().Header().Get(headerIfMatch) != "" {
+		return http.StatusPreconditionFailed
+	}
+	if status, ok := codeStatus[err.Error()]; ok {
+		return status
+	}
+	for _, k := range kindStatus {
+		if errors.Is(err, k.kind) {
+			return k.status
+		}
+	}
+	return http.StatusInternalServerError
+}
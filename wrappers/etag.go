@@ -0,0 +1,42 @@
+package wrappers
+
+import (
+	"github.com/Festum/Vibe/controllers"
+	"github.com/labstack/echo"
+	"strconv"
+	"strings"
+)
+
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+)
+
+// setETag tags the response with the version of the account.
+func setETag(c echo.Context, u *controllers.User) {
+	c.Response().Header().Set(headerETag, `"`+strconv.FormatInt(u.Version, 10)+`"`)
+}
+
+// ifMatch returns the account version the client read, taken from If-Match.
+// A missing header is refused when required. Zero, returned for "*" or an
+// optional missing header, skips the version check.
+func ifMatch(c echo.Context, required bool) (int64, error) {
+	tag := strings.TrimSpace(c.Request().Header().Get(headerIfMatch))
+	switch tag {
+	case "":
+		if required {
+			return 0, errPreconditionRequired
+		}
+		return 0, nil
+	case "*":
+		return 0, nil
+	}
+
+	tag = strings.TrimPrefix(tag, "W/")
+	v, err := strconv.ParseInt(strings.Trim(tag, `"`), 10, 64)
+	if err != nil || v <= 0 {
+		// A tag we never issued cannot match.
+		return 0, controllers.ErrVersionConflict
+	}
+	return v, nil
+}
@@ -0,0 +1,57 @@
+package wrappers
+
+import (
+	"github.com/Festum/Vibe/controllers"
+	"github.com/labstack/echo"
+	"gopkg.in/mgo.v2/bson"
+	"net/http"
+	"os"
+)
+
+// RequestExport queues an export of the caller's data. The response points
+// to the export status, which carries the download link once ready.
+func (h *Handlers) RequestExport(c echo.Context) error {
+	ut := new(controllers.User).ParseToken(c.Get("user"))
+	if controllers.IsImpersonated(ut) {
+		return errImpersonating
+	}
+
+	u := &controllers.User{Username: ut["iss"].(string)}
+	u.Actor = actorFromContext(c)
+	e, err := u.RequestExport()
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, h.Prefix+"/account/export/"+e.ID.Hex())
+	return c.JSON(http.StatusAccepted, e)
+}
+
+func (h *Handlers) GetExport(c echo.Context) error {
+	if !bson.IsObjectIdHex(c.Param("id")) {
+		return controllers.ErrNotFound
+	}
+	ut := new(controllers.User).ParseToken(c.Get("user"))
+	e, err := controllers.GetExport(ut["iss"].(string), bson.ObjectIdHex(c.Param("id")))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, e)
+}
+
+// DownloadExport serves an archive through its signed link, no token needed.
+func (h *Handlers) DownloadExport(c echo.Context) error {
+	path, e, err := controllers.OpenExport(c.Param("id"), c.QueryParam("expires"), c.QueryParam("signature"))
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return errExportGone
+	}
+	defer f.Close()
+
+	return c.Attachment(f, e.Username+"-export.zip")
+}
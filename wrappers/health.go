@@ -0,0 +1,29 @@
+package wrappers
+
+import (
+	"github.com/Festum/Vibe/controllers"
+	"github.com/labstack/echo"
+	"net/http"
+)
+
+// liveness is the body of a liveness probe.
+type liveness struct {
+	Status string `json:"status"`
+}
+
+// Healthz tells the process is up and serving, whatever its dependencies.
+func (h *Handlers) Healthz(c echo.Context) error {
+	return c.JSON(http.StatusOK, &liveness{Status: controllers.CheckOK})
+}
+
+// Readyz runs the readiness checks and answers 503 when one of them failed,
+// with the status and latency of every check.
+func (h *Handlers) Readyz(c echo.Context) error {
+	r := controllers.Ready()
+	status := http.StatusOK
+	if r.Status != controllers.CheckOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(status, r)
+}
@@ -0,0 +1,39 @@
+package wrappers
+
+import (
+	"github.com/Festum/Vibe/controllers"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo"
+)
+
+// locale returns the language of the response: the Language of the
+// signed-in user when texts exist for it, else the Accept-Language header.
+func locale(c echo.Context) string {
+	if lang, ok := c.Get("locale").(string); ok {
+		return lang
+	}
+
+	prefs := []string{}
+	if token, ok := c.Get("user").(*jwt.Token); ok {
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			if iss, ok := claims["iss"].(string); ok {
+				u := controllers.User{Username: iss}
+				if u.Get() == nil {
+					prefs = append(prefs, u.Language)
+				}
+			}
+		}
+	}
+	prefs = append(prefs, c.Request().Header().Get("Accept-Language"))
+
+	lang := controllers.Locale(prefs...)
+	c.Set("locale", lang)
+	return lang
+}
+
+// msg returns the text of a message code for the caller.
+func msg(c echo.Context, code string, params map[string]interface{}) string {
+	lang := locale(c)
+	c.Response().Header().Set("Content-Language", lang)
+	return controllers.Localize(lang, code, params)
+}
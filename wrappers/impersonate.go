@@ -0,0 +1,39 @@
+package wrappers
+
+import (
+	"github.com/labstack/echo"
+	"net/http"
+	"time"
+)
+
+type (
+	impersonationRequest struct {
+		Reason string `json:"reason"`
+	}
+
+	// impersonation is a short-lived token acting as username.
+	impersonation struct {
+		Token     string `json:"token"`
+		Username  string `json:"username"`
+		ExpiresAt string `json:"expires_at"`
+	}
+)
+
+func (h *Handlers) Impersonate(c echo.Context) error {
+	req := new(impersonationRequest)
+	if err := c.Bind(req); err != nil {
+		return badRequest(err)
+	}
+
+	u := userFromParam(c.Param("id"))
+	token, expire, err := u.Impersonate(*actorFromContext(c), req.Reason)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &impersonation{
+		Token:     token,
+		Username:  u.Username,
+		ExpiresAt: expire.UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,53 @@
+package wrappers
+
+import (
+	"fmt"
+	"github.com/Festum/Vibe/utils"
+	"github.com/labstack/echo"
+	"net/http"
+)
+
+const (
+	APITitle   = "Vibe"
+	APIVersion = "0.0.1"
+)
+
+// docsPage shows the OpenAPI document, whose path is formatted in, with
+// Swagger UI.
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<title>Vibe API</title>
+	<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+	<div id="docs"></div>
+	<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+	<script>SwaggerUIBundle({url: %q, dom_id: "#docs"});</script>
+</body>
+</html>
+`
+
+// Spec is the OpenAPI document of the enabled routes of h.
+func (h *Handlers) Spec() map[string]interface{} {
+	o := &utils.OpenAPI{
+		Title:       APITitle,
+		Version:     APIVersion,
+		Description: "A minimalist member system: authentication, accounts and their administration.",
+		Problem:     new(problem),
+		ProblemType: ProblemContentType,
+	}
+	for _, r := range h.Enabled() {
+		o.Add(r.Operation)
+	}
+	return o.Document()
+}
+
+func (h *Handlers) OpenAPI(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.Spec())
+}
+
+func (h *Handlers) Docs(c echo.Context) error {
+	return c.HTML(http.StatusOK, fmt.Sprintf(docsPage, h.Prefix+"/openapi.json"))
+}
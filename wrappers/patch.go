@@ -0,0 +1,90 @@
+package wrappers
+
+import (
+	"encoding/json"
+	"github.com/Festum/Vibe/controllers"
+	"github.com/labstack/echo"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const maxPatchSize = 1 << 20
+
+// Patch changes the caller's own account. Fields reserved to administrators
+// are only unlocked for an admin who is not impersonating someone.
+func (h *Handlers) Patch(c echo.Context) error {
+	ut := new(controllers.User).ParseToken(c.Get("user"))
+	u := &controllers.User{Username: ut["iss"].(string)}
+	if err := u.Get(); err != nil {
+		return err
+	}
+
+	return patchAccount(c, u, u.Role == "admin" && !controllers.IsImpersonated(ut))
+}
+
+func isPatchRequest(c echo.Context) bool {
+	switch contentType(c) {
+	case controllers.MergePatchType, controllers.JSONPatchType:
+		return true
+	}
+	return false
+}
+
+func contentType(c echo.Context) string {
+	ct := c.Request().Header().Get(echo.HeaderContentType)
+	return strings.ToLower(strings.TrimSpace(strings.Split(ct, ";")[0]))
+}
+
+func patchAccount(c echo.Context, u *controllers.User, asAdmin bool) error {
+	v, err := ifMatch(c, true)
+	if err != nil {
+		return err
+	}
+	u.Version = v
+
+	body, err := ioutil.ReadAll(io.LimitReader(c.Request().Body(), maxPatchSize))
+	if err != nil {
+		return badRequest(err)
+	}
+
+	u.Actor = actorFromContext(c)
+	if err := u.Patch(contentType(c), body, asAdmin); err != nil {
+		return err
+	}
+
+	setETag(c, u)
+	return c.JSON(http.StatusOK, u)
+}
+
+// bindUpdate binds the body of an update into u and returns the JSON names
+// of the members it carries, so omitted fields keep their stored value.
+func bindUpdate(c echo.Context, u *controllers.User) ([]string, error) {
+	fields := []string{}
+	if contentType(c) != echo.MIMEApplicationJSON {
+		if err := c.Bind(u); err != nil {
+			return nil, err
+		}
+		for name := range c.FormParams() {
+			fields = append(fields, name)
+		}
+		return fields, nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(c.Request().Body(), maxPatchSize))
+	if err != nil {
+		return nil, err
+	}
+	members := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &members); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, u); err != nil {
+		return nil, err
+	}
+	for name := range members {
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
@@ -0,0 +1,176 @@
+package wrappers
+
+import (
+	"github.com/Festum/Vibe/controllers"
+	"github.com/Festum/Vibe/models"
+	"github.com/Festum/Vibe/utils"
+	"github.com/labstack/echo"
+	"github.com/labstack/echo/middleware"
+	"net/http"
+)
+
+// The groups of routes, their OpenAPI tag, which can be disabled as a whole.
+const (
+	GroupAuth         = "auth"
+	GroupRegistration = "registration"
+	GroupSocial       = "social"
+	GroupAccount      = "account"
+	GroupBilling      = "billing"
+	GroupAdmin        = "admin"
+	GroupDocs         = "docs"
+	GroupHealth       = "health"
+)
+
+// Groups lists every group of routes.
+var Groups = []string{GroupAuth, GroupRegistration, GroupSocial, GroupAccount, GroupBilling, GroupAdmin, GroupDocs, GroupHealth}
+
+// Route is an endpoint of Vibe, registered by Mount and described by the
+// OpenAPI document from the same entry so both cannot drift apart.
+type Route struct {
+	utils.Operation
+	Admin   bool //restricted to administrators, Secured as well
+	Handler echo.HandlerFunc
+}
+
+var (
+	ifMatchParam    = utils.Param{Name: headerIfMatch, In: "header"}
+	ifMatchRequired = utils.Param{Name: headerIfMatch, In: "header", Required: true}
+	limitParam      = utils.Param{Name: "limit", Type: "integer"}
+	patchTypes      = []string{controllers.MergePatchType, controllers.JSONPatchType}
+)
+
+// Routes lists every endpoint of Vibe, at the root.
+func (h *Handlers) Routes() []Route {
+	user := new(controllers.User)
+	routes := []Route{
+		{Operation: utils.Operation{Method: echo.GET, Path: "/auth/:id", Summary: "Check an authentication", Tag: GroupSocial}, Handler: h.Check},
+		{Operation: utils.Operation{Method: echo.POST, Path: "/login", Summary: "Log in by email or username", Tag: GroupAuth,
+			Request: new(credentials), Consumes: []string{echo.MIMEApplicationJSON, echo.MIMEApplicationForm}, Response: new(tokenResponse)}, Handler: h.Login},
+		{Operation: utils.Operation{Method: echo.POST, Path: "/register", Summary: "Create an account", Tag: GroupRegistration,
+			Request: user, Response: user, Status: http.StatusCreated}, Handler: h.Register},
+
+		{Operation: utils.Operation{Method: echo.GET, Path: "/account", Summary: "Greet the owner of the token", Tag: GroupAccount, Secured: true,
+			Produces: echo.MIMETextPlain}, Handler: h.TokenResolve},
+		{Operation: utils.Operation{Method: echo.POST, Path: "/account/update", Summary: "Update the account", Tag: GroupAccount, Secured: true,
+			Params: []utils.Param{ifMatchParam}, Request: user, Response: user}, Handler: h.Update},
+		{Operation: utils.Operation{Method: echo.GET, Path: "/account/info", Summary: "Get the account", Tag: GroupAccount, Secured: true,
+			Response: user}, Handler: h.Get},
+		{Operation: utils.Operation{Method: echo.GET, Path: "/account/info/:id", Summary: "Get the account", Tag: GroupAccount, Secured: true,
+			Response: user}, Handler: h.Get},
+		{Operation: utils.Operation{Method: echo.PUT, Path: "/account/:id", Summary: "Replace the account", Tag: GroupAccount, Secured: true,
+			Params: []utils.Param{ifMatchRequired}, Request: user, Response: user}, Handler: h.Update},
+		{Operation: utils.Operation{Method: echo.PATCH, Path: "/account", Summary: "Patch the account", Tag: GroupAccount, Secured: true,
+			Params: []utils.Param{ifMatchRequired}, Request: new(interface{}), Consumes: patchTypes, Response: user}, Handler: h.Patch},
+		{Operation: utils.Operation{Method: echo.DELETE, Path: "/account", Summary: "Move an account to the trash", Tag: GroupAccount, Secured: true,
+			Request: user, Status: http.StatusNoContent}, Handler: h.Delete},
+		{Operation: utils.Operation{Method: echo.POST, Path: "/account/export", Summary: "Request an export of the personal data", Tag: GroupAccount, Secured: true,
+			Response: new(models.Export), Status: http.StatusAccepted}, Handler: h.RequestExport},
+		{Operation: utils.Operation{Method: echo.GET, Path: "/account/export/:id", Summary: "Get an export and its download link", Tag: GroupAccount, Secured: true,
+			Response: new(models.Export)}, Handler: h.GetExport},
+		{Operation: utils.Operation{Method: echo.POST, Path: "/account/anonymize", Summary: "Erase the personal data of the account", Tag: GroupAccount, Secured: true,
+			Status: http.StatusNoContent}, Handler: h.Anonymize},
+		{Operation: utils.Operation{Method: echo.GET, Path: "/account/billing", Summary: "Get the payment method", Tag: GroupBilling, Secured: true,
+			Response: new(models.Billing)}, Handler: h.GetBilling},
+		{Operation: utils.Operation{Method: echo.PUT, Path: "/account/billing", Summary: "Replace the payment method", Tag: GroupBilling, Secured: true,
+			Request: new(models.Billing), Response: new(models.Billing)}, Handler: h.SaveBilling},
+		{Operation: utils.Operation{Method: echo.DELETE, Path: "/account/billing", Summary: "Remove the payment method", Tag: GroupBilling, Secured: true,
+			Status: http.StatusNoContent}, Handler: h.RemoveBilling},
+		{Operation: utils.Operation{Method: echo.POST, Path: "/account/avatar", Summary: "Upload an avatar", Tag: GroupAccount, Secured: true,
+			Request: new(avatarUpload), Consumes: []string{echo.MIMEMultipartForm}, Response: user}, Handler: h.UploadAvatar},
+		{Operation: utils.Operation{Method: echo.DELETE, Path: "/account/avatar", Summary: "Remove the avatar", Tag: GroupAccount, Secured: true,
+			Response: user}, Handler: h.RemoveAvatar},
+
+		{Operation: utils.Operation{Method: echo.GET, Path: "/export/:id", Summary: "Download an export by its signed link", Tag: GroupAccount,
+			Params: []utils.Param{{Name: "expires", Required: true}, {Name: "signature", Required: true}}, Produces: "application/zip"}, Handler: h.DownloadExport},
+		{Operation: utils.Operation{Method: echo.GET, Path: "/avatars/:id/:stamp/:file", Summary: "Get an avatar variant", Tag: GroupAccount,
+			Produces: "image/*"}, Handler: h.ServeAvatar},
+
+		{Operation: utils.Operation{Method: echo.GET, Path: "/users", Summary: "Search accounts", Tag: GroupAdmin,
+			Params: []utils.Param{
+				{Name: "q"}, {Name: "role"}, {Name: "country"}, {Name: "phone"},
+				{Name: "username"}, {Name: "email"}, {Name: "display_name"},
+				{Name: "disabled", Type: "boolean"}, {Name: "deleted", Type: "boolean"},
+				{Name: "created_after", Format: "date-time"}, {Name: "created_until", Format: "date-time"},
+				{Name: "login_after", Format: "date-time"}, {Name: "login_until", Format: "date-time"},
+				{Name: "sort"}, {Name: "cursor"}, limitParam,
+			}, Response: new(controllers.UserPage)}, Admin: true, Handler: h.ListUsers},
+		{Operation: utils.Operation{Method: echo.GET, Path: "/users/:id", Summary: "Get an account", Tag: GroupAdmin,
+			Response: user}, Admin: true, Handler: h.GetUser},
+		{Operation: utils.Operation{Method: echo.PATCH, Path: "/users/:id", Summary: "Patch or update an account", Tag: GroupAdmin,
+			Params: []utils.Param{ifMatchRequired}, Request: new(interface{}),
+			Consumes: append([]string{echo.MIMEApplicationJSON}, patchTypes...), Response: user}, Admin: true, Handler: h.UpdateUser},
+		{Operation: utils.Operation{Method: echo.DELETE, Path: "/users/:id", Summary: "Move an account to the trash", Tag: GroupAdmin,
+			Status: http.StatusNoContent}, Admin: true, Handler: h.DeleteUser},
+		{Operation: utils.Operation{Method: echo.POST, Path: "/users/:id/restore", Summary: "Restore an account from the trash", Tag: GroupAdmin,
+			Response: user}, Admin: true, Handler: h.RestoreUser},
+		{Operation: utils.Operation{Method: echo.POST, Path: "/users/:id/anonymize", Summary: "Erase the personal data of an account", Tag: GroupAdmin,
+			Response: user}, Admin: true, Handler: h.AnonymizeUser},
+		{Operation: utils.Operation{Method: echo.POST, Path: "/users/:id/impersonate", Summary: "Act as a user for support", Tag: GroupAdmin,
+			Request: new(impersonationRequest), Response: new(impersonation)}, Admin: true, Handler: h.Impersonate},
+
+		{Operation: utils.Operation{Method: echo.GET, Path: "/audit", Summary: "List audit entries", Tag: GroupAdmin,
+			Params: []utils.Param{
+				{Name: "actor"}, {Name: "target"}, {Name: "action"},
+				{Name: "after", Type: "integer"},
+				{Name: "since", Format: "date-time"}, {Name: "until", Format: "date-time"},
+				limitParam,
+			}, Response: []models.AuditEntry{}}, Admin: true, Handler: h.ListAudit},
+		{Operation: utils.Operation{Method: echo.GET, Path: "/audit/verify", Summary: "Verify the audit chain", Tag: GroupAdmin,
+			Response: new(auditVerification)}, Admin: true, Handler: h.VerifyAudit},
+
+		{Operation: utils.Operation{Method: echo.GET, Path: "/webhooks", Summary: "List webhooks", Tag: GroupAdmin,
+			Response: []models.Webhook{}}, Admin: true, Handler: h.ListWebhooks},
+		{Operation: utils.Operation{Method: echo.POST, Path: "/webhooks", Summary: "Subscribe a webhook", Tag: GroupAdmin,
+			Request: new(models.Webhook), Response: new(models.Webhook), Status: http.StatusCreated}, Admin: true, Handler: h.AddWebhook},
+		{Operation: utils.Operation{Method: echo.DELETE, Path: "/webhooks/:id", Summary: "Remove a webhook", Tag: GroupAdmin,
+			Status: http.StatusNoContent}, Admin: true, Handler: h.RemoveWebhook},
+		{Operation: utils.Operation{Method: echo.GET, Path: "/webhooks/deliveries", Summary: "List webhook deliveries", Tag: GroupAdmin,
+			Params: []utils.Param{{Name: "status"}, limitParam}, Response: []models.Delivery{}}, Admin: true, Handler: h.ListDeliveries},
+		{Operation: utils.Operation{Method: echo.POST, Path: "/webhooks/deliveries/:id/replay", Summary: "Replay a delivery", Tag: GroupAdmin,
+			Status: http.StatusAccepted}, Admin: true, Handler: h.ReplayDelivery},
+
+		{Operation: utils.Operation{Method: echo.GET, Path: "/openapi.json", Summary: "Get this OpenAPI document", Tag: GroupDocs,
+			Response: new(interface{})}, Handler: h.OpenAPI},
+		{Operation: utils.Operation{Method: echo.GET, Path: "/docs", Summary: "Browse the API documentation", Tag: GroupDocs,
+			Produces: echo.MIMETextHTML}, Handler: h.Docs},
+
+		{Operation: utils.Operation{Method: echo.GET, Path: "/healthz", Summary: "Tell the server is alive", Tag: GroupHealth,
+			Response: new(liveness)}, Handler: h.Healthz},
+		{Operation: utils.Operation{Method: echo.GET, Path: "/readyz", Summary: "Check the database, log directories and signing key", Tag: GroupHealth,
+			Response: new(controllers.Readiness)}, Handler: h.Readyz},
+	}
+	for i := range routes {
+		routes[i].Secured = routes[i].Secured || routes[i].Admin
+	}
+	return routes
+}
+
+// Enabled lists the routes served by h: those of the groups not disabled,
+// under Prefix.
+func (h *Handlers) Enabled() []Route {
+	routes := []Route{}
+	for _, r := range h.Routes() {
+		if h.Disabled[r.Tag] {
+			continue
+		}
+		r.Path = h.Prefix + r.Path
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+// Mount registers the enabled routes of h on e, behind Middleware then the
+// client certificate or JWT check and the admin check they need.
+func (h *Handlers) Mount(e *echo.Echo) {
+	jwt := middleware.JWTWithConfig(h.JWTCheck())
+	for _, r := range h.Enabled() {
+		m := append([]echo.MiddlewareFunc{}, h.Middleware...)
+		if r.Secured {
+			m = append(m, h.ClientCert, jwt)
+		}
+		if r.Admin {
+			m = append(m, h.AdminOnly)
+		}
+		e.Add(r.Method, r.Path, r.Handler, m...)
+	}
+}
@@ -0,0 +1,72 @@
+package wrappers
+
+import (
+	"github.com/Festum/Vibe/controllers"
+	"github.com/Festum/Vibe/models"
+	"github.com/labstack/echo"
+	"gopkg.in/mgo.v2/bson"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handlers) ListWebhooks(c echo.Context) error {
+	hooks, err := controllers.Webhooks()
+	if err != nil {
+		return err
+	}
+	// Secrets are write-only.
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+
+	return c.JSON(http.StatusOK, hooks)
+}
+
+func (h *Handlers) AddWebhook(c echo.Context) error {
+	hook := new(models.Webhook)
+	if err := c.Bind(hook); err != nil {
+		return badRequest(err)
+	}
+	if err := controllers.AddWebhook(hook); err != nil {
+		return err
+	}
+	hook.Secret = ""
+
+	return c.JSON(http.StatusCreated, hook)
+}
+
+func (h *Handlers) RemoveWebhook(c echo.Context) error {
+	if !bson.IsObjectIdHex(c.Param("id")) {
+		return controllers.ErrNotFound
+	}
+	if err := controllers.RemoveWebhook(bson.ObjectIdHex(c.Param("id"))); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handlers) ListDeliveries(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	ds, err := controllers.Deliveries(c.QueryParam("status"), limit)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, ds)
+}
+
+func (h *Handlers) ReplayDelivery(c echo.Context) error {
+	if !bson.IsObjectIdHex(c.Param("id")) {
+		return controllers.ErrNotFound
+	}
+	n, err := controllers.ReplayDeliveries(bson.ObjectIdHex(c.Param("id")))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return controllers.ErrNotFound
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}